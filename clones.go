@@ -0,0 +1,320 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// A clone has to span at least this many lines of code, made of at least this many tokens, so that short and common
+// snippets like `if err != nil { return err }` are not reported
+const (
+	minCloneLines  = 6
+	minCloneTokens = 50
+)
+
+// Kinds of clone groups
+const (
+	cloneExact      = "exact"      // the code is the same, token by token
+	cloneNormalized = "normalized" // the code is the same once identifiers and literals are ignored
+)
+
+// Fragment is the code of a function body, as hashed lines, that clones are searched in
+type Fragment struct {
+	File  string
+	Lines []CodeLine
+}
+
+// CodeLine is a line of code, hashed as is and with its identifiers and literals normalized
+type CodeLine struct {
+	Line       int
+	Tokens     int
+	Exact      uint64
+	Normalized uint64
+}
+
+// CloneGroup is a piece of code that is found in more than one place
+type CloneGroup struct {
+	Kind   string
+	Lines  int // lines of code in each clone
+	Clones []Clone
+}
+
+// Clone is a place where the code of a CloneGroup is found
+type Clone struct {
+	File      string
+	StartLine int
+	EndLine   int
+}
+
+// findFragments hashes the lines of code of each func body in the file. src is the content of the file.
+func findFragments(fset *token.FileSet, file *ast.File, src []byte, filePath string) []Fragment {
+	var fragments []Fragment
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		start, end := fset.Position(fn.Body.Lbrace), fset.Position(fn.Body.Rbrace)
+		lines := hashLines(src[start.Offset+1:end.Offset], start.Line)
+		if len(lines) >= minCloneLines {
+			fragments = append(fragments, Fragment{File: filePath, Lines: lines})
+		}
+	}
+	return fragments
+}
+
+// hashLines scans the code and hashes the tokens of each line. firstLine is the line number the code starts at.
+// Comments and automatically inserted semicolons are left out.
+func hashLines(src []byte, firstLine int) []CodeLine {
+	var lines []CodeLine
+	var exact, normalized []string
+	var line int
+	flush := func() {
+		if len(exact) > 0 {
+			lines = append(lines, CodeLine{
+				Line:       line,
+				Tokens:     len(exact),
+				Exact:      hashTokens(exact),
+				Normalized: hashTokens(normalized),
+			})
+		}
+		exact, normalized = exact[:0], normalized[:0]
+	}
+
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", fset.Base(), len(src)), src, nil, 0)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		if l := fset.Position(pos).Line + firstLine - 1; l != line {
+			flush()
+			line = l
+		}
+		switch {
+		case tok == token.IDENT:
+			exact, normalized = append(exact, lit), append(normalized, "$")
+		case tok.IsLiteral():
+			exact, normalized = append(exact, lit), append(normalized, "#")
+		default:
+			exact, normalized = append(exact, tok.String()), append(normalized, tok.String())
+		}
+	}
+	flush()
+	return lines
+}
+
+func hashTokens(tokens []string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(tokens, " ")))
+	return h.Sum64()
+}
+
+// window is a run of minCloneLines lines, starting at the index start of the fragment at index frag
+type window struct {
+	frag  int
+	start int
+}
+
+// findClones finds the code that is duplicated across the fragments. Windows of minCloneLines lines that are the same
+// once normalized are found first, and then extended for as long as all their clones keep being the same.
+func findClones(fragments []Fragment) []CloneGroup {
+	// Clones of each window of lines, by the hash of the window
+	var byHash = make(map[uint64][]window)
+	for i, f := range fragments {
+		for start := 0; start+minCloneLines <= len(f.Lines); start++ {
+			h := fnv.New64a()
+			for _, l := range f.Lines[start : start+minCloneLines] {
+				fmt.Fprintf(h, "%x,", l.Normalized)
+			}
+			byHash[h.Sum64()] = append(byHash[h.Sum64()], window{frag: i, start: start})
+		}
+	}
+
+	// Sets of windows that are the same, keyed by their windows
+	var sets = make(map[string][]window)
+	for _, windows := range byHash {
+		windows = nonOverlapping(windows)
+		if len(windows) > 1 {
+			sets[windowsKey(windows, 0)] = windows
+		}
+	}
+
+	var groups []CloneGroup
+	for _, windows := range sets {
+		// The set is part of a longer clone that starts earlier
+		if _, ok := sets[windowsKey(windows, -1)]; ok {
+			continue
+		}
+		length := minCloneLines
+		for !overlapping(windows, length+1) {
+			if _, ok := sets[windowsKey(windows, length-minCloneLines+1)]; !ok {
+				break
+			}
+			length++
+		}
+
+		group := CloneGroup{Kind: cloneExact, Lines: length}
+		var tokens int
+		first := fragments[windows[0].frag].Lines[windows[0].start : windows[0].start+length]
+		for _, w := range windows {
+			lines := fragments[w.frag].Lines[w.start : w.start+length]
+			for i := range lines {
+				if lines[i].Exact != first[i].Exact {
+					group.Kind = cloneNormalized
+				}
+			}
+			group.Clones = append(group.Clones, Clone{
+				File:      fragments[w.frag].File,
+				StartLine: lines[0].Line,
+				EndLine:   lines[len(lines)-1].Line,
+			})
+		}
+		for _, l := range first {
+			tokens += l.Tokens
+		}
+		if tokens < minCloneTokens {
+			continue
+		}
+		groups = append(groups, group)
+	}
+
+	for i := range groups {
+		sort.Slice(groups[i].Clones, func(a, b int) bool {
+			return cloneLess(groups[i].Clones[a], groups[i].Clones[b])
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.duplicatedLines() != b.duplicatedLines() {
+			return a.duplicatedLines() > b.duplicatedLines()
+		}
+		if a.Clones[0] != b.Clones[0] {
+			return cloneLess(a.Clones[0], b.Clones[0])
+		}
+		return len(a.Clones) > len(b.Clones)
+	})
+	return groups
+}
+
+// distinctClones drops the groups that have a clone overlapping with a clone of a larger group. groups must be sorted,
+// the largest first.
+func distinctClones(groups []CloneGroup) []CloneGroup {
+	var distinct []CloneGroup
+	var kept []Clone
+	for _, g := range groups {
+		overlaps := false
+		for _, c := range g.Clones {
+			for _, k := range kept {
+				if c.File == k.File && c.StartLine <= k.EndLine && k.StartLine <= c.EndLine {
+					overlaps = true
+				}
+			}
+		}
+		if overlaps {
+			continue
+		}
+		distinct = append(distinct, g)
+		kept = append(kept, g.Clones...)
+	}
+	return distinct
+}
+
+// nonOverlapping drops the windows that overlap with a previous one of the same fragment, as a run of repeated lines
+// is not a clone of itself
+func nonOverlapping(windows []window) []window {
+	var kept []window
+	var lastStart = make(map[int]int)
+	for _, w := range windows {
+		if last, ok := lastStart[w.frag]; ok && w.start-last < minCloneLines {
+			continue
+		}
+		lastStart[w.frag] = w.start
+		kept = append(kept, w)
+	}
+	return kept
+}
+
+// overlapping tells whether any two of the windows would overlap, if they were length lines long
+func overlapping(windows []window, length int) bool {
+	for i := 1; i < len(windows); i++ {
+		prev, w := windows[i-1], windows[i]
+		if prev.frag == w.frag && w.start-prev.start < length {
+			return true
+		}
+	}
+	return false
+}
+
+// windowsKey identifies a set of windows, each moved by shift lines
+func windowsKey(windows []window, shift int) string {
+	var parts []string
+	for _, w := range windows {
+		parts = append(parts, fmt.Sprintf("%d:%d", w.frag, w.start+shift))
+	}
+	return strings.Join(parts, ",")
+}
+
+func cloneLess(a, b Clone) bool {
+	return locationLess(Location{File: a.File, Line: a.StartLine}, Location{File: b.File, Line: b.StartLine})
+}
+
+// duplicatedLines is the number of lines of code in all the clones of the group
+func (g CloneGroup) duplicatedLines() int {
+	return g.Lines * len(g.Clones)
+}
+
+// detectClones finds the clones between the files of the report, and counts the duplicated lines of code of each file
+// and of the whole report
+func detectClones(report Report) Report {
+	var fragments []Fragment
+	var byFile = make(map[string][]Fragment)
+	for _, f := range report.Files {
+		fragments = append(fragments, f.Fragments...)
+		byFile[f.Path] = f.Fragments
+	}
+	groups := findClones(fragments)
+	report.Clones = distinctClones(groups)
+
+	// Lines of code that are in at least one clone
+	var duplicated = make(map[string]map[int]bool)
+	for _, g := range groups {
+		for _, c := range g.Clones {
+			if duplicated[c.File] == nil {
+				duplicated[c.File] = make(map[int]bool)
+			}
+			for _, f := range byFile[c.File] {
+				for _, l := range f.Lines {
+					if l.Line >= c.StartLine && l.Line <= c.EndLine {
+						duplicated[c.File][l.Line] = true
+					}
+				}
+			}
+		}
+	}
+
+	report.Total.LinesDuplicated = 0
+	for i, f := range report.Files {
+		report.Files[i].LinesDuplicated = len(duplicated[f.Path])
+		report.Total.LinesDuplicated += report.Files[i].LinesDuplicated
+	}
+	return report
+}
+
+// duplicationRatio is the share of the lines of code, including error checking, that are duplicated
+func duplicationRatio(r Results) float64 {
+	if r.LinesOfCode+r.LinesOfErrCheck == 0 {
+		return 0
+	}
+	return float64(r.LinesDuplicated) / float64(r.LinesOfCode+r.LinesOfErrCheck)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashLines(t *testing.T) {
+	a := hashLines([]byte("\n\tx := f(1) // one\n\n\ty := g(2)\n"), 10)
+	b := hashLines([]byte("\n\tname := f(\"a\")\n\ty := g(2)\n"), 1)
+
+	assert.Equal(t, 2, len(a))
+	assert.Equal(t, 11, a[0].Line)
+	assert.Equal(t, 13, a[1].Line)
+	assert.Equal(t, 6, a[0].Tokens)
+
+	// Identifiers and literals only matter to the exact hash
+	assert.True(t, a[0].Exact != b[0].Exact)
+	assert.Equal(t, a[0].Normalized, b[0].Normalized)
+	assert.Equal(t, a[1].Exact, b[1].Exact)
+}
+
+func TestDetectClones(t *testing.T) {
+	body := `
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return data, nil
+}
+`
+	renamed := `
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", name, err)
+	}
+	defer file.Close()
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	return content, nil
+}
+`
+	sources := map[string]string{
+		"a.go": "package sample\n\nfunc readA(path string) ([]byte, error) {" + body +
+			"\nfunc readB(path string) ([]byte, error) {" + body,
+		"b.go": "package sample\n\nfunc short() {\n\treturn\n}\n\nfunc readC(name string) ([]byte, error) {" + renamed,
+	}
+
+	var report Report
+	for _, path := range []string{"a.go", "b.go"} {
+		fset := token.NewFileSet()
+		src := []byte(sources[path])
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fr := FileResults{Path: path, Fragments: findFragments(fset, file, src, path)}
+		report.Files = append(report.Files, fr)
+	}
+	// short() is too short to be searched for clones
+	assert.Equal(t, 1, len(report.Files[1].Fragments))
+
+	report = detectClones(report)
+	assert.Equal(t, []CloneGroup{
+		{
+			Kind:  cloneNormalized,
+			Lines: 10,
+			Clones: []Clone{
+				{File: "a.go", StartLine: 4, EndLine: 13},
+				{File: "a.go", StartLine: 17, EndLine: 26},
+				{File: "b.go", StartLine: 8, EndLine: 17},
+			},
+		},
+	}, report.Clones)
+	assert.Equal(t, 20, report.Files[0].LinesDuplicated)
+	assert.Equal(t, 10, report.Files[1].LinesDuplicated)
+	assert.Equal(t, 30, report.Total.LinesDuplicated)
+}
+
+func TestFindClonesExact(t *testing.T) {
+	var lines []CodeLine
+	for i := 1; i <= 7; i++ {
+		lines = append(lines, CodeLine{Line: i, Tokens: 10, Exact: uint64(i), Normalized: uint64(i)})
+	}
+	fragments := []Fragment{
+		{File: "a.go", Lines: lines},
+		{File: "b.go", Lines: lines},
+		{File: "c.go", Lines: lines[:5]},
+	}
+
+	assert.Equal(t, []CloneGroup{
+		{
+			Kind:  cloneExact,
+			Lines: 7,
+			Clones: []Clone{
+				{File: "a.go", StartLine: 1, EndLine: 7},
+				{File: "b.go", StartLine: 1, EndLine: 7},
+			},
+		},
+	}, findClones(fragments))
+}
+
+func TestDistinctClones(t *testing.T) {
+	large := CloneGroup{Lines: 8, Clones: []Clone{{File: "a.go", StartLine: 1, EndLine: 8}, {File: "b.go", StartLine: 1, EndLine: 8}}}
+	overlapping := CloneGroup{Lines: 6, Clones: []Clone{{File: "a.go", StartLine: 5, EndLine: 10}, {File: "c.go", StartLine: 1, EndLine: 6}}}
+	elsewhere := CloneGroup{Lines: 6, Clones: []Clone{{File: "a.go", StartLine: 20, EndLine: 25}, {File: "c.go", StartLine: 1, EndLine: 6}}}
+
+	assert.Equal(t, []CloneGroup{large, elsewhere}, distinctClones([]CloneGroup{large, overlapping, elsewhere}))
+}
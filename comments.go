@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CommentStats classifies the lines of comments in a unit of code. Each line that is only a comment falls in exactly one
+// of the line categories, so that they add up to the LinesOfComments of the Results. Comments that share a line with
+// code, e.g. trailing comments, are counted apart as LinesOfInline. Markers are listed regardless of the category of the
+// comment they are in.
+type CommentStats struct {
+	LinesOfDocs             int
+	LinesOfLicense          int
+	LinesOfDirectives       int
+	LinesOfCommentedOutCode int
+	LinesOfOther            int
+	LinesOfInline           int
+
+	Markers []Marker
+}
+
+// Marker is a TODO, FIXME, HACK or XXX note left in a comment
+type Marker struct {
+	Kind     string
+	Text     string
+	Location Location
+}
+
+// markerRegexp matches a marker at the start of a comment line, e.g. "// TODO(user): fix this" or " * FIXME: later"
+var markerRegexp = regexp.MustCompile(`^(?://|/\*)?[\s*]*(TODO|FIXME|HACK|XXX)\b(?:\([^)]*\))?:?\s*(.*)`)
+
+// classifyComments goes through all the comments in the file, and classifies each line of them
+func classifyComments(fset *token.FileSet, file *ast.File, src []byte, filePath string) CommentStats {
+	var s CommentStats
+
+	docGroups := docCommentGroups(file)
+	packageLine := fset.Position(file.Package).Line
+
+	for _, group := range file.Comments {
+		startLine := fset.Position(group.Pos()).Line
+
+		// Decide the category of the group as a whole, directives are picked out line by line later
+		var lines *int
+		switch {
+		case startLine < packageLine && group != file.Doc && isLicenseComment(group.Text()):
+			lines = &s.LinesOfLicense
+		case docGroups[group]:
+			lines = &s.LinesOfDocs
+		case isCommentedOutCode(group.Text()):
+			lines = &s.LinesOfCommentedOutCode
+		default:
+			lines = &s.LinesOfOther
+		}
+
+		for _, c := range group.List {
+			start, end := fset.Position(c.Pos()).Line, fset.Position(c.End()).Line
+			numLines := end - start + 1
+
+			// The lines that the comment shares with code are inline comments, whatever the comment says
+			inline := inlineLines(fset, src, c)
+			s.LinesOfInline += inline
+			numLines -= inline
+
+			if isDirective(c.Text) {
+				s.LinesOfDirectives += numLines
+			} else {
+				*lines += numLines
+			}
+
+			// Markers, looked for line by line as a block comment can have many
+			for i, line := range strings.Split(c.Text, "\n") {
+				m := markerRegexp.FindStringSubmatch(strings.TrimSpace(line))
+				if m == nil {
+					continue
+				}
+				s.Markers = append(s.Markers, Marker{
+					Kind: m[1],
+					Text: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[2]), "*/")),
+					Location: Location{
+						File: filePath,
+						Line: start + i,
+					},
+				})
+			}
+		}
+	}
+
+	return s
+}
+
+// inlineLines returns the number of lines of the comment that also have code on them: the first line if there is code
+// before the comment, and the last line if there is code after it
+func inlineLines(fset *token.FileSet, src []byte, c *ast.Comment) int {
+	tf := fset.File(c.Pos())
+	start, end := tf.Offset(c.Pos()), tf.Offset(c.End())
+
+	lineStart := tf.Offset(tf.LineStart(tf.Line(c.Pos())))
+	codeBefore := len(bytes.TrimSpace(src[lineStart:start])) > 0
+
+	lineEnd := bytes.IndexByte(src[end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(src) - end
+	}
+	after := bytes.TrimSpace(src[end : end+lineEnd])
+	codeAfter := len(after) > 0 && !bytes.HasPrefix(after, []byte("//")) && !bytes.HasPrefix(after, []byte("/*"))
+
+	switch {
+	case codeBefore && codeAfter && tf.Line(c.Pos()) != tf.Line(c.End()):
+		return 2
+	case codeBefore || codeAfter:
+		return 1
+	}
+	return 0
+}
+
+// docCommentGroups returns the comment groups that document the package or a declaration
+func docCommentGroups(file *ast.File) map[*ast.CommentGroup]bool {
+	var groups = make(map[*ast.CommentGroup]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		var doc *ast.CommentGroup
+		switch n := n.(type) {
+		case *ast.File:
+			doc = n.Doc
+		case *ast.FuncDecl:
+			doc = n.Doc
+		case *ast.GenDecl:
+			doc = n.Doc
+		case *ast.TypeSpec:
+			doc = n.Doc
+		case *ast.ValueSpec:
+			doc = n.Doc
+		case *ast.Field:
+			doc = n.Doc
+		}
+		if doc != nil {
+			groups[doc] = true
+		}
+		return true
+	})
+	return groups
+}
+
+func isLicenseComment(text string) bool {
+	text = strings.ToLower(text)
+	return strings.Contains(text, "copyright") || strings.Contains(text, "license")
+}
+
+// isCommentedOutCode guesses whether the text of a comment is Go code, by trying to parse it as either statements or
+// declarations. Text that parses only as plain identifiers and selectors (e.g. "done", or "e.g. foo"), as labels (e.g.
+// "Note: x") or as a keyword followed by words (e.g. "return early") is not considered code, and neither is text with a
+// marker such as "TODO: fix".
+func isCommentedOutCode(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if markerRegexp.MatchString(strings.TrimSpace(line)) {
+			return false
+		}
+	}
+
+	fset := token.NewFileSet()
+
+	// Declarations
+	file, err := parser.ParseFile(fset, "", "package p\n"+text, 0)
+	if err == nil && len(file.Decls) > 0 {
+		return true
+	}
+
+	// Statements
+	file, err = parser.ParseFile(fset, "", "package p\nfunc _() {\n"+text+"\n}", 0)
+	if err != nil {
+		return false
+	}
+	body := file.Decls[0].(*ast.FuncDecl).Body
+	for _, stmt := range body.List {
+		if !isProseStmt(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// isProseStmt tells whether the statement is one that prose happens to parse as: a bare identifier or selector, a return,
+// break, continue or goto followed by nothing but words, or a label that is not followed by a loop, switch, select or
+// block like the labels of actual code are
+func isProseStmt(stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.BranchStmt:
+		return true
+	case *ast.ReturnStmt:
+		for _, result := range stmt.Results {
+			if _, ok := result.(*ast.Ident); !ok {
+				return false
+			}
+		}
+		return true
+	case *ast.ExprStmt:
+		switch stmt.X.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			return true
+		}
+	case *ast.LabeledStmt:
+		switch stmt.Stmt.(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.BlockStmt:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func addCommentStats(a, b CommentStats) CommentStats {
+	var s CommentStats
+	s.LinesOfDocs = a.LinesOfDocs + b.LinesOfDocs
+	s.LinesOfLicense = a.LinesOfLicense + b.LinesOfLicense
+	s.LinesOfDirectives = a.LinesOfDirectives + b.LinesOfDirectives
+	s.LinesOfCommentedOutCode = a.LinesOfCommentedOutCode + b.LinesOfCommentedOutCode
+	s.LinesOfOther = a.LinesOfOther + b.LinesOfOther
+	s.LinesOfInline = a.LinesOfInline + b.LinesOfInline
+
+	s.Markers = append(s.Markers, a.Markers...)
+	s.Markers = append(s.Markers, b.Markers...)
+	sort.SliceStable(s.Markers, func(i, j int) bool {
+		return locationLess(s.Markers[i].Location, s.Markers[j].Location)
+	})
+	return s
+}
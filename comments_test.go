@@ -0,0 +1,189 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyComments(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want CommentStats
+	}{
+		{
+			name: "sample test file 1",
+			src:  sampleFileA,
+			want: CommentStats{
+				LinesOfDocs:  3,
+				LinesOfOther: 1,
+			},
+		},
+		{
+			name: "all kinds of comments",
+			src: `// Copyright 2019 The Authors. All rights reserved.
+// Use of this source code is governed by the MIT license.
+
+// Package sample is a sample.
+package sample
+
+//go:generate stringer -type=Kind
+
+// Kind is a kind
+//
+//go:noinline
+type Kind int
+
+func run() {
+	// x := compute(1, 2)
+	// if x > 2 {
+	// 	return
+	// }
+
+	// TODO(teejays): handle the error
+	doSomething() //nolint:errcheck
+
+	/*
+		FIXME: this is slow
+		HACK this is a hack
+	*/
+	// done
+}
+`,
+			want: CommentStats{
+				LinesOfDocs:             3,
+				LinesOfLicense:          2,
+				LinesOfDirectives:       2,
+				LinesOfCommentedOutCode: 4,
+				LinesOfOther:            6,
+				LinesOfInline:           1,
+				Markers: []Marker{
+					{Kind: "TODO", Text: "handle the error", Location: Location{File: "sample.go", Line: 20}},
+					{Kind: "FIXME", Text: "this is slow", Location: Location{File: "sample.go", Line: 24}},
+					{Kind: "HACK", Text: "this is a hack", Location: Location{File: "sample.go", Line: 25}},
+				},
+			},
+		},
+		{
+			name: "trailing comments",
+			src: `package sample
+
+// Stats counts the ways errors are handled
+type Stats struct {
+	Returned int // return err
+	Panics   int // panic(err)
+}
+
+const limit = 3 // x := limit
+
+func run() {
+	/* leading */ run()
+	run() /* a long
+	trailing comment */
+}
+`,
+			want: CommentStats{
+				LinesOfDocs:   1,
+				LinesOfOther:  1,
+				LinesOfInline: 5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "sample.go", tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, classifyComments(fset, file, []byte(tt.src), "sample.go"))
+		})
+	}
+}
+
+func TestIsCommentedOutCode(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{
+			name: "prose",
+			text: "Get names of all files",
+			want: false,
+		},
+		{
+			name: "single word",
+			text: "done",
+			want: false,
+		},
+		{
+			name: "statements",
+			text: "r.NumOfFiles = 1\nreturn r",
+			want: true,
+		},
+		{
+			name: "function call",
+			text: "fmt.Println(r)",
+			want: true,
+		},
+		{
+			name: "declaration",
+			text: "func unused() {}",
+			want: true,
+		},
+		{
+			name: "todo marker",
+			text: "TODO: fix",
+			want: false,
+		},
+		{
+			name: "fixme marker",
+			text: "FIXME: later",
+			want: false,
+		},
+		{
+			name: "marker on a code line",
+			text: "TODO: r.Close()",
+			want: false,
+		},
+		{
+			name: "label",
+			text: "Note: x",
+			want: false,
+		},
+		{
+			name: "abbreviation",
+			text: "e.g. foo",
+			want: false,
+		},
+		{
+			name: "prose starting with a keyword",
+			text: "return early",
+			want: false,
+		},
+		{
+			name: "prose starting with a branch",
+			text: "continue here",
+			want: false,
+		},
+		{
+			name: "return of a call",
+			text: "return f(x)",
+			want: true,
+		},
+		{
+			name: "labeled loop",
+			text: "outer:\nfor _, f := range files {\n\tbreak outer\n}",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isCommentedOutCode(tt.text))
+		})
+	}
+}
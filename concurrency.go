@@ -0,0 +1,122 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// Concurrency counts the concurrency constructs used in a unit of code. The sync types are counted wherever they are
+// referred to, e.g. in a struct field, a var declaration or a composite literal.
+type Concurrency struct {
+	Goroutines    int // go f()
+	ChanMakes     int // make(chan T)
+	ChanSends     int // ch <- v
+	ChanReceives  int // <-ch
+	Selects       int // select {}
+	Mutexes       int // sync.Mutex
+	RWMutexes     int // sync.RWMutex
+	WaitGroups    int // sync.WaitGroup
+	Onces         int // sync.Once
+	ContextParams int // func f(ctx context.Context)
+}
+
+// Total is the number of concurrency constructs
+func (c Concurrency) Total() int {
+	return c.Goroutines + c.ChanMakes + c.ChanSends + c.ChanReceives + c.Selects +
+		c.Mutexes + c.RWMutexes + c.WaitGroups + c.Onces + c.ContextParams
+}
+
+// countConcurrency counts the concurrency constructs in the node, which can be a whole file or a single func. pkgNames
+// are the names of the packages imported by the file, as returned by importNames.
+func countConcurrency(node ast.Node, pkgNames map[string]string) Concurrency {
+	var c Concurrency
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			c.Goroutines++
+
+		case *ast.CallExpr:
+			if fun, ok := n.Fun.(*ast.Ident); ok && fun.Name == "make" && len(n.Args) > 0 {
+				if _, ok := n.Args[0].(*ast.ChanType); ok {
+					c.ChanMakes++
+				}
+			}
+
+		case *ast.SendStmt:
+			c.ChanSends++
+
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				c.ChanReceives++
+			}
+
+		case *ast.SelectStmt:
+			c.Selects++
+
+		case *ast.SelectorExpr:
+			pkg, name, ok := pkgFunc(n, pkgNames)
+			if !ok || pkg != "sync" {
+				break
+			}
+			switch name {
+			case "Mutex":
+				c.Mutexes++
+			case "RWMutex":
+				c.RWMutexes++
+			case "WaitGroup":
+				c.WaitGroups++
+			case "Once":
+				c.Onces++
+			}
+
+		case *ast.FuncType:
+			if n.Params == nil {
+				break
+			}
+			for _, field := range n.Params.List {
+				pkg, name, ok := pkgFunc(field.Type, pkgNames)
+				if !ok || pkg != "context" || name != "Context" {
+					continue
+				}
+				// Unnamed params still count once
+				c.ContextParams += maxInt(len(field.Names), 1)
+			}
+		}
+		return true
+	})
+	return c
+}
+
+// mostConcurrent returns the functions that use any concurrency construct, the ones using the most first
+func mostConcurrent(funcs []FuncStats) []FuncStats {
+	var ranked []FuncStats
+	for _, f := range funcs {
+		if f.Concurrency.Total() > 0 {
+			ranked = append(ranked, f)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Concurrency.Total() != b.Concurrency.Total() {
+			return a.Concurrency.Total() > b.Concurrency.Total()
+		}
+		return locationLess(a.Location, b.Location)
+	})
+	return ranked
+}
+
+func addConcurrency(a, b Concurrency) Concurrency {
+	var c Concurrency
+	c.Goroutines = a.Goroutines + b.Goroutines
+	c.ChanMakes = a.ChanMakes + b.ChanMakes
+	c.ChanSends = a.ChanSends + b.ChanSends
+	c.ChanReceives = a.ChanReceives + b.ChanReceives
+	c.Selects = a.Selects + b.Selects
+	c.Mutexes = a.Mutexes + b.Mutexes
+	c.RWMutexes = a.RWMutexes + b.RWMutexes
+	c.WaitGroups = a.WaitGroups + b.WaitGroups
+	c.Onces = a.Onces + b.Onces
+	c.ContextParams = a.ContextParams + b.ContextParams
+	return c
+}
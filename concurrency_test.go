@@ -0,0 +1,103 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountConcurrency(t *testing.T) {
+	src := `package sample
+
+import (
+	"context"
+	gosync "sync"
+)
+
+type cache struct {
+	mu    gosync.RWMutex
+	once  gosync.Once
+	items map[string]string
+}
+
+func fetchAll(ctx context.Context, urls []string) []string {
+	var wg gosync.WaitGroup
+	results := make(chan string, len(urls))
+	for _, url := range urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			results <- fetch(ctx, url)
+		}(url)
+	}
+	wg.Wait()
+
+	var all []string
+	for range urls {
+		select {
+		case r := <-results:
+			all = append(all, r)
+		case <-ctx.Done():
+			return all
+		}
+	}
+	return all
+}
+
+func fetch(_ context.Context, url string) string {
+	var mu gosync.Mutex
+	mu.Lock()
+	defer mu.Unlock()
+	return url
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgNames := importNames(file)
+
+	assert.Equal(t, Concurrency{
+		Goroutines:    1,
+		ChanMakes:     1,
+		ChanSends:     1,
+		ChanReceives:  2,
+		Selects:       1,
+		Mutexes:       1,
+		RWMutexes:     1,
+		WaitGroups:    1,
+		Onces:         1,
+		ContextParams: 2,
+	}, countConcurrency(file, pkgNames))
+
+	funcs, err := analyzeFuncs(fset, file, []byte(src), "sample.go")
+	assert.NoError(t, err)
+	assert.Equal(t, Concurrency{
+		Goroutines:    1,
+		ChanMakes:     1,
+		ChanSends:     1,
+		ChanReceives:  2,
+		Selects:       1,
+		WaitGroups:    1,
+		ContextParams: 1,
+	}, funcs[0].Concurrency)
+	assert.Equal(t, Concurrency{Mutexes: 1, ContextParams: 1}, funcs[1].Concurrency)
+}
+
+func TestMostConcurrent(t *testing.T) {
+	funcs := []FuncStats{
+		{Name: "a", Location: Location{File: "a.go", Line: 1}, Concurrency: Concurrency{Goroutines: 1}},
+		{Name: "b", Location: Location{File: "a.go", Line: 10}},
+		{Name: "c", Location: Location{File: "a.go", Line: 20}, Concurrency: Concurrency{Goroutines: 1, ChanSends: 2}},
+		{Name: "d", Location: Location{File: "a.go", Line: 30}, Concurrency: Concurrency{Mutexes: 1}},
+	}
+
+	var names []string
+	for _, f := range mostConcurrent(funcs) {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"c", "a", "d"}, names)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Declarations is an inventory of the top-level declarations in a unit of code
+type Declarations struct {
+	Structs     int
+	Interfaces  int
+	TypeAliases int
+	OtherTypes  int // named types that are not structs, interfaces or aliases (e.g. type Kind int)
+	Funcs       int
+	Methods     int
+	Consts      int
+	Vars        int
+
+	Exported   int
+	Unexported int
+}
+
+// countDeclarations takes an inventory of the top-level declarations in the file
+func countDeclarations(file *ast.File) Declarations {
+	var d Declarations
+
+	countName := func(name *ast.Ident) {
+		if name.Name == "_" {
+			return
+		}
+		if name.IsExported() {
+			d.Exported++
+		} else {
+			d.Unexported++
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv != nil {
+				d.Methods++
+			} else {
+				d.Funcs++
+			}
+			countName(decl.Name)
+
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					switch {
+					case spec.Assign.IsValid():
+						d.TypeAliases++
+					case isStructType(spec.Type):
+						d.Structs++
+					case isInterfaceType(spec.Type):
+						d.Interfaces++
+					default:
+						d.OtherTypes++
+					}
+					countName(spec.Name)
+
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Name == "_" {
+							continue
+						}
+						if decl.Tok == token.CONST {
+							d.Consts++
+						} else {
+							d.Vars++
+						}
+						countName(name)
+					}
+				}
+			}
+		}
+	}
+
+	return d
+}
+
+func isStructType(expr ast.Expr) bool {
+	_, ok := expr.(*ast.StructType)
+	return ok
+}
+
+func isInterfaceType(expr ast.Expr) bool {
+	_, ok := expr.(*ast.InterfaceType)
+	return ok
+}
+
+func addDeclarations(a, b Declarations) Declarations {
+	var d Declarations
+	d.Structs = a.Structs + b.Structs
+	d.Interfaces = a.Interfaces + b.Interfaces
+	d.TypeAliases = a.TypeAliases + b.TypeAliases
+	d.OtherTypes = a.OtherTypes + b.OtherTypes
+	d.Funcs = a.Funcs + b.Funcs
+	d.Methods = a.Methods + b.Methods
+	d.Consts = a.Consts + b.Consts
+	d.Vars = a.Vars + b.Vars
+
+	d.Exported = a.Exported + b.Exported
+	d.Unexported = a.Unexported + b.Unexported
+	return d
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountDeclarations(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Declarations
+	}{
+		{
+			name: "sample test file 1",
+			src:  sampleFileA,
+			want: Declarations{
+				Structs:  7,
+				Funcs:    1,
+				Exported: 8,
+			},
+		},
+		{
+			name: "all kinds of declarations",
+			src: `package sample
+
+type (
+	Alias = string
+	kind  int
+)
+
+type Reader interface {
+	Read() error
+}
+
+type file struct{}
+
+func (f *file) Read() error { return nil }
+
+func (f file) close() {}
+
+func New() Reader { return &file{} }
+
+const (
+	KindA kind = iota
+	kindB
+)
+
+var _ Reader = (*file)(nil)
+
+var a, B = 1, 2
+`,
+			want: Declarations{
+				Structs:     1,
+				Interfaces:  1,
+				TypeAliases: 1,
+				OtherTypes:  1,
+				Funcs:       1,
+				Methods:     2,
+				Consts:      2,
+				Vars:        2,
+				Exported:    6,
+				Unexported:  5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "sample.go", tt.src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, countDeclarations(file))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// ruleDeferInLoop is reported for every defer inside a loop, as the deferred calls pile up until the function returns
+const ruleDeferInLoop = "defer-in-loop"
+
+// DeferPanic represents the use of defer, panic and recover in a unit of code. The Name of each Symbol is the function
+// it is found in.
+type DeferPanic struct {
+	Defers        int
+	DefersInLoops []Symbol
+	Panics        []Symbol // panic calls outside of the main and init funcs
+	Recovers      []Symbol
+}
+
+// analyzeDeferPanic finds the defer statements and the panic and recover calls in the funcs of the file. A defer in a
+// func literal that is called in a loop is not counted as being in the loop.
+func analyzeDeferPanic(fset *token.FileSet, file *ast.File, filePath string) DeferPanic {
+	var d DeferPanic
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		name := funcName(fn)
+		canPanic := fn.Recv == nil && (fn.Name.Name == "main" || fn.Name.Name == "init")
+		symbol := func(kind string, pos token.Pos) Symbol {
+			return Symbol{Name: name, Kind: kind, Location: Location{File: filePath, Line: fset.Position(pos).Line}}
+		}
+
+		var stack []ast.Node
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, n)
+
+			switch n := n.(type) {
+			case *ast.DeferStmt:
+				d.Defers++
+				if inLoop(stack) {
+					d.DefersInLoops = append(d.DefersInLoops, symbol("defer", n.Pos()))
+				}
+			case *ast.CallExpr:
+				fun, ok := n.Fun.(*ast.Ident)
+				if !ok {
+					break
+				}
+				if fun.Name == "panic" && !canPanic {
+					d.Panics = append(d.Panics, symbol("panic", n.Pos()))
+				}
+				if fun.Name == "recover" {
+					d.Recovers = append(d.Recovers, symbol("recover", n.Pos()))
+				}
+			}
+			return true
+		})
+	}
+	return d
+}
+
+// inLoop tells whether the last node of the stack is inside the body of a loop of the same func
+func inLoop(stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			return true
+		}
+	}
+	return false
+}
+
+// checkDefers reports every defer inside a loop
+func checkDefers(d DeferPanic) []Violation {
+	var violations []Violation
+	for _, s := range d.DefersInLoops {
+		violations = append(violations, Violation{
+			Rule:    ruleDeferInLoop,
+			Message: fmt.Sprintf("defer in a loop in function %s only runs when the function returns", s.Name),
+			File:    s.Location.File,
+			Region:  Region{StartLine: s.Location.Line, EndLine: s.Location.Line},
+		})
+	}
+	return violations
+}
+
+func addDeferPanic(a, b DeferPanic) DeferPanic {
+	var d DeferPanic
+	d.Defers = a.Defers + b.Defers
+	d.DefersInLoops = mergeSymbols(a.DefersInLoops, b.DefersInLoops)
+	d.Panics = mergeSymbols(a.Panics, b.Panics)
+	d.Recovers = mergeSymbols(a.Recovers, b.Recovers)
+	return d
+}
+
+// mergeSymbols returns the symbols of both a and b, sorted by location
+func mergeSymbols(a, b []Symbol) []Symbol {
+	var symbols []Symbol
+	symbols = append(symbols, a...)
+	symbols = append(symbols, b...)
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return locationLess(symbols[i].Location, symbols[j].Location)
+	})
+	return symbols
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeDeferPanic(t *testing.T) {
+	src := `package sample
+
+func closeAll(files []*File) {
+	for _, f := range files {
+		defer f.Close()
+	}
+	for _, f := range files {
+		func() {
+			defer f.Close()
+		}()
+	}
+}
+
+func (s *Server) serve() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered: %v", r)
+		}
+	}()
+	if s == nil {
+		panic("nil server")
+	}
+	return nil
+}
+
+func init() {
+	panic("init")
+}
+
+func main() {
+	panic(err)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := analyzeDeferPanic(fset, file, "sample.go")
+	assert.Equal(t, DeferPanic{
+		Defers: 3,
+		DefersInLoops: []Symbol{
+			{Name: "closeAll", Kind: "defer", Location: Location{File: "sample.go", Line: 5}},
+		},
+		Panics: []Symbol{
+			{Name: "Server.serve", Kind: "panic", Location: Location{File: "sample.go", Line: 21}},
+		},
+		Recovers: []Symbol{
+			{Name: "Server.serve", Kind: "recover", Location: Location{File: "sample.go", Line: 16}},
+		},
+	}, d)
+
+	assert.Equal(t, []Violation{
+		{
+			Rule:    ruleDeferInLoop,
+			Message: "defer in a loop in function closeAll only runs when the function returns",
+			File:    "sample.go",
+			Region:  Region{StartLine: 5, EndLine: 5},
+		},
+	}, checkDefers(d))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// riskyDirectives are the directives that get around the safety of the language or the runtime, and deserve a review
+var riskyDirectives = []string{"go:linkname", "go:nosplit", "go:noescape", "go:uintptrescapes"}
+
+// Directive is an instruction to the compiler or a tool, written as a comment
+type Directive struct {
+	Kind     string   // e.g. "go:generate", "nolint", "+build"
+	Args     string   // the rest of the directive after the kind
+	Linters  []string // for nolint directives, the linters that are suppressed ("all" if none are named)
+	Risky    bool
+	Location Location
+}
+
+// parseDirective parses the text of a line comment (including the leading //) into a Directive. It returns false if
+// the comment is not a directive.
+func parseDirective(text string) (Directive, bool) {
+	var d Directive
+
+	switch {
+	case strings.HasPrefix(text, "//go:"), strings.HasPrefix(text, "//line "), strings.HasPrefix(text, "//export "):
+		d.Kind, d.Args = splitDirective(strings.TrimPrefix(text, "//"))
+
+	case strings.HasPrefix(text, "// +build"):
+		d.Kind, d.Args = splitDirective(strings.TrimPrefix(text, "// "))
+
+	case strings.HasPrefix(text, "//nolint"):
+		d.Kind = "nolint"
+		// e.g. //nolint:errcheck,gosec // explanation
+		rest := strings.TrimPrefix(text, "//nolint")
+		if i := strings.Index(rest, "//"); i >= 0 {
+			rest = rest[:i]
+		}
+		rest = strings.TrimSpace(rest)
+		d.Args = rest
+		if strings.HasPrefix(rest, ":") {
+			for _, linter := range strings.Split(strings.TrimPrefix(rest, ":"), ",") {
+				linter = strings.TrimSpace(linter)
+				if linter != "" {
+					d.Linters = append(d.Linters, linter)
+				}
+			}
+		}
+		if len(d.Linters) == 0 {
+			d.Linters = []string{"all"}
+		}
+
+	default:
+		return d, false
+	}
+
+	d.Risky = sliceContainsString(riskyDirectives, d.Kind)
+	return d, true
+}
+
+func splitDirective(text string) (string, string) {
+	fields := strings.SplitN(text, " ", 2)
+	if len(fields) == 1 {
+		return fields[0], ""
+	}
+	return fields[0], strings.TrimSpace(fields[1])
+}
+
+// isDirective tells whether the comment is an instruction to the compiler or a tool, rather than text for a reader
+func isDirective(text string) bool {
+	_, ok := parseDirective(text)
+	return ok
+}
+
+// findDirectives lists all the directives in the file
+func findDirectives(fset *token.FileSet, file *ast.File, filePath string) []Directive {
+	var directives []Directive
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			d, ok := parseDirective(c.Text)
+			if !ok {
+				continue
+			}
+			d.Location = Location{
+				File: filePath,
+				Line: fset.Position(c.Pos()).Line,
+			}
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// countDirectives counts the directives by kind, and the nolint suppressions by linter
+func countDirectives(directives []Directive) (map[string]int, map[string]int) {
+	var byKind = make(map[string]int)
+	var byLinter = make(map[string]int)
+	for _, d := range directives {
+		byKind[d.Kind]++
+		for _, linter := range d.Linters {
+			byLinter[linter]++
+		}
+	}
+	return byKind, byLinter
+}
+
+func sortDirectives(directives []Directive) {
+	sort.SliceStable(directives, func(i, j int) bool {
+		return locationLess(directives[i].Location, directives[j].Location)
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDirective(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   Directive
+		wantOk bool
+	}{
+		{
+			name:   "regular comment",
+			text:   "// go:generate is not a directive with a space",
+			wantOk: false,
+		},
+		{
+			name:   "go generate",
+			text:   "//go:generate stringer -type=Kind",
+			want:   Directive{Kind: "go:generate", Args: "stringer -type=Kind"},
+			wantOk: true,
+		},
+		{
+			name:   "linkname",
+			text:   "//go:linkname nanotime runtime.nanotime",
+			want:   Directive{Kind: "go:linkname", Args: "nanotime runtime.nanotime", Risky: true},
+			wantOk: true,
+		},
+		{
+			name:   "nosplit",
+			text:   "//go:nosplit",
+			want:   Directive{Kind: "go:nosplit", Risky: true},
+			wantOk: true,
+		},
+		{
+			name:   "build constraint",
+			text:   "// +build linux",
+			want:   Directive{Kind: "+build", Args: "linux"},
+			wantOk: true,
+		},
+		{
+			name:   "nolint for all linters",
+			text:   "//nolint",
+			want:   Directive{Kind: "nolint", Linters: []string{"all"}},
+			wantOk: true,
+		},
+		{
+			name:   "nolint for some linters with explanation",
+			text:   "//nolint:errcheck, gosec // closing can't fail here",
+			want:   Directive{Kind: "nolint", Args: ":errcheck, gosec", Linters: []string{"errcheck", "gosec"}},
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseDirective(tt.text)
+			assert.Equal(t, tt.wantOk, ok)
+			if ok {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFindDirectives(t *testing.T) {
+	src := `package sample
+
+import _ "unsafe"
+
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+func run() {
+	defer file.Close() //nolint:errcheck
+	fmt.Println() //nolint
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := findDirectives(fset, file, "sample.go")
+	assert.Equal(t, []Directive{
+		{Kind: "go:linkname", Args: "nanotime runtime.nanotime", Risky: true, Location: Location{File: "sample.go", Line: 5}},
+		{Kind: "nolint", Args: ":errcheck", Linters: []string{"errcheck"}, Location: Location{File: "sample.go", Line: 9}},
+		{Kind: "nolint", Linters: []string{"all"}, Location: Location{File: "sample.go", Line: 10}},
+	}, got)
+
+	byKind, byLinter := countDirectives(got)
+	assert.Equal(t, []namedCount{{"nolint", 2}, {"go:linkname", 1}}, sortedCounts(byKind))
+	assert.Equal(t, []namedCount{{"all", 1}, {"errcheck", 1}}, sortedCounts(byLinter))
+}
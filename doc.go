@@ -0,0 +1,112 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// Kinds of symbols
+const (
+	symbolFunc   = "func"
+	symbolMethod = "method"
+	symbolType   = "type"
+	symbolConst  = "const"
+)
+
+// DocCoverage tracks how many of the exported identifiers in a unit of code have a doc comment
+type DocCoverage struct {
+	Exported     int
+	Documented   int
+	Undocumented []Symbol
+}
+
+// Symbol is a named identifier declared in code
+type Symbol struct {
+	Name     string
+	Kind     string
+	Location Location
+}
+
+// Percent is the percentage of exported identifiers that are documented. Code with nothing exported is fully covered.
+func (c DocCoverage) Percent() float64 {
+	if c.Exported == 0 {
+		return 100
+	}
+	return percentOf(c.Documented, c.Exported)
+}
+
+// checkDocCoverage finds the exported funcs, methods, types and consts in the file, and whether they are documented.
+// Methods are only considered if their receiver type is exported too.
+func checkDocCoverage(fset *token.FileSet, file *ast.File, filePath string) DocCoverage {
+	var c DocCoverage
+
+	add := func(name string, pos token.Pos, kind string, documented bool) {
+		c.Exported++
+		if documented {
+			c.Documented++
+			return
+		}
+		c.Undocumented = append(c.Undocumented, Symbol{
+			Name: name,
+			Kind: kind,
+			Location: Location{
+				File: filePath,
+				Line: fset.Position(pos).Line,
+			},
+		})
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Name.IsExported() {
+				continue
+			}
+			// A method without a receiver does not compile, but it parses
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				add(decl.Name.Name, decl.Pos(), symbolFunc, decl.Doc != nil)
+				continue
+			}
+			if ast.IsExported(receiverTypeName(decl.Recv.List[0].Type)) {
+				add(funcName(decl), decl.Pos(), symbolMethod, decl.Doc != nil)
+			}
+
+		case *ast.GenDecl:
+			// A doc comment on a group, e.g. const ( ... ), documents all the specs in it
+			groupDoc := decl.Doc != nil
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Name.IsExported() {
+						add(spec.Name.Name, spec.Pos(), symbolType, groupDoc || spec.Doc != nil)
+					}
+				case *ast.ValueSpec:
+					if decl.Tok != token.CONST {
+						continue
+					}
+					for _, name := range spec.Names {
+						if name.IsExported() {
+							add(name.Name, name.Pos(), symbolConst, groupDoc || spec.Doc != nil || spec.Comment != nil)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return c
+}
+
+func addDocCoverage(a, b DocCoverage) DocCoverage {
+	var c DocCoverage
+	c.Exported = a.Exported + b.Exported
+	c.Documented = a.Documented + b.Documented
+
+	c.Undocumented = append(c.Undocumented, a.Undocumented...)
+	c.Undocumented = append(c.Undocumented, b.Undocumented...)
+	sort.SliceStable(c.Undocumented, func(i, j int) bool {
+		return locationLess(c.Undocumented[i].Location, c.Undocumented[j].Location)
+	})
+	return c
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDocCoverage(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want DocCoverage
+	}{
+		{
+			name: "sample test file 1",
+			src:  sampleFileA,
+			want: DocCoverage{
+				Exported:   8,
+				Documented: 3,
+				Undocumented: []Symbol{
+					{Name: "ConfigLogSourceSettings", Kind: symbolType, Location: Location{File: "config.go", Line: 33}},
+					{Name: "ConfigStatsType", Kind: symbolType, Location: Location{File: "config.go", Line: 41}},
+					{Name: "ConfigStatsTypeSourceSetting", Kind: symbolType, Location: Location{File: "config.go", Line: 48}},
+					{Name: "ConfigAlertType", Kind: symbolType, Location: Location{File: "config.go", Line: 55}},
+					{Name: "ConfigAlertTypeSourceSetting", Kind: symbolType, Location: Location{File: "config.go", Line: 63}},
+				},
+			},
+		},
+		{
+			name: "funcs, methods and consts",
+			src: `package sample
+
+// Kinds of things
+const (
+	KindA = iota
+	KindB
+)
+
+const KindC = 3 // the third kind
+
+const KindD = 4
+
+type store struct{}
+
+func (s *store) Get() {}
+
+// Client talks to the server
+type Client struct{}
+
+func (c *Client) Do() {}
+
+// New returns a Client
+func New() *Client { return nil }
+
+func helper() {}
+`,
+			want: DocCoverage{
+				Exported:   7,
+				Documented: 5,
+				Undocumented: []Symbol{
+					{Name: "KindD", Kind: symbolConst, Location: Location{File: "config.go", Line: 11}},
+					{Name: "Client.Do", Kind: symbolMethod, Location: Location{File: "config.go", Line: 20}},
+				},
+			},
+		},
+		{
+			name: "method without a receiver",
+			src:  "package sample\n\nfunc () M() {}\n",
+			want: DocCoverage{
+				Exported:   1,
+				Documented: 0,
+				Undocumented: []Symbol{
+					{Name: "M", Kind: symbolFunc, Location: Location{File: "config.go", Line: 3}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "config.go", tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, checkDocCoverage(fset, file, "config.go"))
+		})
+	}
+}
+
+func TestDocCoveragePercent(t *testing.T) {
+	assert.Equal(t, 100.0, DocCoverage{}.Percent())
+	assert.Equal(t, 75.0, DocCoverage{Exported: 4, Documented: 3}.Percent())
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrorHandling counts the different ways errors are handled in a unit of code. It works on the syntax alone, so an
+// error is recognized by its name (err, or ending in Err). Without the types, the calls whose results are not used can
+// only be matched by name against the funcs that are known to return an error: the funcs of the project and some of the
+// standard library, by package and name, and their methods by name alone, whatever the type.
+type ErrorHandling struct {
+	BareReturns  int // return err
+	Wrapped      int // return fmt.Errorf("...: %w", err), or errors.Wrap(err, "...")
+	Unwrapped    int // return fmt.Errorf("...: %v", err), where the error can no longer be unwrapped
+	Panics       int // panic(err)
+	Fatals       int // log.Fatal(err), and the like
+	IgnoredCalls int // _ = f(), or v, _ := f(), where f is known to return an error
+	DroppedCalls int // f(), where f is known to return an error
+}
+
+// Kinds of the calls whose results are not used
+const (
+	callDropped = "call"    // f()
+	callIgnored = "ignored" // _ = f(), or v, _ := f()
+)
+
+// Percent is the percentage of returned errors that are wrapped with context. Code that returns no errors is fully
+// compliant.
+func (e ErrorHandling) Percent() float64 {
+	total := e.BareReturns + e.Wrapped + e.Unwrapped
+	if total == 0 {
+		return 100
+	}
+	return percentOf(e.Wrapped, total)
+}
+
+// stdErrorFuncs are the funcs and methods of the standard library whose error is most often left unchecked, named the way
+// the calls are, i.e. by import path, or with a dot before methods
+var stdErrorFuncs = []string{
+	".Close",
+	"encoding/json.Unmarshal",
+	"io.Copy",
+	"io.ReadFull",
+	"net/http.ListenAndServe",
+	"os.Chdir",
+	"os.Chmod",
+	"os.Mkdir",
+	"os.MkdirAll",
+	"os.Remove",
+	"os.RemoveAll",
+	"os.Rename",
+	"os.Setenv",
+	"os.WriteFile",
+}
+
+// wrapFuncs are the funcs of the errors packages (pkg/errors, cockroachdb/errors etc.) that add context to an error
+var wrapFuncs = []string{"Wrap", "Wrapf", "WithMessage", "WithMessagef", "WithStack"}
+
+// analyzeErrorHandling counts how errors are handled in the file. It also returns the funcs declared in the file that
+// return an error, and the calls whose results are dropped or whose last result is ignored, so that the errors that
+// are not handled can be counted once all the files of the project have been seen.
+func analyzeErrorHandling(fset *token.FileSet, file *ast.File, filePath string) (ErrorHandling, []string, []Symbol) {
+	var e ErrorHandling
+	var errorFuncs []string
+	var callStmts []Symbol
+
+	pkgNames := importNames(file)
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !returnsError(fn.Type) {
+			continue
+		}
+		if fn.Recv != nil {
+			errorFuncs = append(errorFuncs, "."+fn.Name.Name)
+		} else {
+			errorFuncs = append(errorFuncs, fn.Name.Name)
+		}
+	}
+	addCall := func(call *ast.CallExpr, kind string) {
+		if name := errorCallName(call.Fun, pkgNames); name != "" {
+			callStmts = append(callStmts, Symbol{
+				Name:     name,
+				Kind:     kind,
+				Location: Location{File: filePath, Line: fset.Position(call.Pos()).Line},
+			})
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ReturnStmt:
+			if len(n.Results) == 0 {
+				break
+			}
+			last := n.Results[len(n.Results)-1]
+			switch {
+			case isErrorIdent(last):
+				e.BareReturns++
+			case isWrappedError(last, pkgNames):
+				e.Wrapped++
+			case isUnwrappedError(last, pkgNames):
+				e.Unwrapped++
+			}
+
+		case *ast.AssignStmt:
+			lastLHS, ok := n.Lhs[len(n.Lhs)-1].(*ast.Ident)
+			if !ok || lastLHS.Name != "_" || len(n.Rhs) != 1 {
+				break
+			}
+			if call, ok := n.Rhs[0].(*ast.CallExpr); ok {
+				addCall(call, callIgnored)
+			}
+
+		case *ast.ExprStmt:
+			call, ok := n.X.(*ast.CallExpr)
+			if !ok {
+				break
+			}
+			if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "panic" && len(call.Args) == 1 && isErrorIdent(call.Args[0]) {
+				e.Panics++
+				break
+			}
+			if pkg, name, ok := pkgFunc(call.Fun, pkgNames); ok && pkg == "log" && strings.HasPrefix(name, "Fatal") {
+				e.Fatals++
+				break
+			}
+			addCall(call, callDropped)
+		}
+		return true
+	})
+
+	return e, errorFuncs, callStmts
+}
+
+func returnsError(ft *ast.FuncType) bool {
+	if ft.Results == nil || len(ft.Results.List) == 0 {
+		return false
+	}
+	last, ok := ft.Results.List[len(ft.Results.List)-1].Type.(*ast.Ident)
+	return ok && last.Name == "error"
+}
+
+func isErrorIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && (ident.Name == "err" || strings.HasSuffix(ident.Name, "Err"))
+}
+
+// errorCallName returns the name a call is matched by against the funcs that return an error: the name of the func,
+// prefixed by the import path if it is from another package, e.g. "os.Remove", or the name of the method prefixed by a
+// dot, e.g. ".Close" for f.Close()
+func errorCallName(fun ast.Expr, pkgNames map[string]string) string {
+	if pkg, name, ok := pkgFunc(fun, pkgNames); ok {
+		return pkg + "." + name
+	}
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return "." + fun.Sel.Name
+	}
+	return ""
+}
+
+// isWrappedError tells whether the expression adds context to an error while keeping it unwrappable
+func isWrappedError(expr ast.Expr, pkgNames map[string]string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	pkg, name, ok := pkgFunc(call.Fun, pkgNames)
+	if !ok {
+		return false
+	}
+	if pkg == "fmt" && name == "Errorf" {
+		return len(call.Args) > 0 && strings.Contains(stringLit(call.Args[0]), "%w")
+	}
+	return path.Base(pkg) == "errors" && sliceContainsString(wrapFuncs, name)
+}
+
+// isUnwrappedError tells whether the expression formats an error into a new one, losing the original
+func isUnwrappedError(expr ast.Expr, pkgNames map[string]string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	pkg, name, ok := pkgFunc(call.Fun, pkgNames)
+	return ok && pkg == "fmt" && name == "Errorf" && hasErrorArg(call)
+}
+
+func hasErrorArg(call *ast.CallExpr) bool {
+	for _, arg := range call.Args {
+		if isErrorIdent(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringLit(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// importNames maps the names that the imported packages are referred by in the file to their import paths
+func importNames(file *ast.File) map[string]string {
+	var names = make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		names[name] = importPath
+	}
+	return names
+}
+
+// pkgFunc splits a call like fmt.Errorf into the import path of the package and the name of the func. It returns false
+// if the func is not from an imported package.
+func pkgFunc(fun ast.Expr, pkgNames map[string]string) (string, string, bool) {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	importPath, ok := pkgNames[x.Name]
+	if !ok {
+		return "", "", false
+	}
+	return importPath, sel.Sel.Name, true
+}
+
+// calledName returns the name of the func or method being called, e.g. "Close" for f.Close()
+func calledName(fun ast.Expr) string {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// knownErrorFuncs returns the funcs that are known to return an error: the ones declared in the files, and the
+// stdErrorFuncs
+func knownErrorFuncs(files []FileResults) map[string]bool {
+	var errorFuncs = make(map[string]bool)
+	for _, name := range stdErrorFuncs {
+		errorFuncs[name] = true
+	}
+	for _, f := range files {
+		for _, name := range f.ErrorFuncs {
+			errorFuncs[qualifiedFuncName(f.Package, name)] = true
+		}
+	}
+	return errorFuncs
+}
+
+// qualifiedFuncName prefixes the name of a func of the package with its import path. Names that are already qualified,
+// and those of methods, are left as they are.
+func qualifiedFuncName(pkg, name string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// countErrorCalls counts the calls of the kind, made from the package, to funcs that are known to return an error
+func countErrorCalls(callStmts []Symbol, kind, pkg string, errorFuncs map[string]bool) int {
+	var n int
+	for _, call := range callStmts {
+		if call.Kind == kind && errorFuncs[qualifiedFuncName(pkg, call.Name)] {
+			n++
+		}
+	}
+	return n
+}
+
+func addErrorHandling(a, b ErrorHandling) ErrorHandling {
+	var e ErrorHandling
+	e.BareReturns = a.BareReturns + b.BareReturns
+	e.Wrapped = a.Wrapped + b.Wrapped
+	e.Unwrapped = a.Unwrapped + b.Unwrapped
+	e.Panics = a.Panics + b.Panics
+	e.Fatals = a.Fatals + b.Fatals
+	e.IgnoredCalls = a.IgnoredCalls + b.IgnoredCalls
+	e.DroppedCalls = a.DroppedCalls + b.DroppedCalls
+	return e
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeErrorHandling(t *testing.T) {
+	src := `package sample
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := check(f); err != nil {
+		return nil, fmt.Errorf("checking %s: %w", path, err)
+	}
+	if err := lock(f); err != nil {
+		return nil, errors.Wrap(err, "locking")
+	}
+	if err := stat(f); err != nil {
+		return nil, fmt.Errorf("stat %s: %s", path, err)
+	}
+	return f, nil
+}
+
+func check(f *File) error {
+	return nil
+}
+
+func (f *File) Close() error {
+	return nil
+}
+
+func count() int {
+	return 0
+}
+
+func main() {
+	f, err := open("a.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	_ = f.Close()
+	n, _ := f.Read(nil)
+	_ = count()
+	check(f)
+	f.Sync()
+	Close()
+	os.Remove("a.txt")
+	_ = json.Unmarshal(nil, f)
+	if n == 0 {
+		panic(err)
+	}
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, errorFuncs, callStmts := analyzeErrorHandling(fset, file, "sample.go")
+	assert.Equal(t, ErrorHandling{
+		BareReturns: 1,
+		Wrapped:     2,
+		Unwrapped:   1,
+		Panics:      1,
+		Fatals:      1,
+	}, e)
+	assert.Equal(t, []string{"open", "check", ".Close"}, errorFuncs)
+	assert.Equal(t, []Symbol{
+		{Name: ".Close", Kind: callIgnored, Location: Location{File: "sample.go", Line: 46}},
+		{Name: ".Read", Kind: callIgnored, Location: Location{File: "sample.go", Line: 47}},
+		{Name: "count", Kind: callIgnored, Location: Location{File: "sample.go", Line: 48}},
+		{Name: "check", Kind: callDropped, Location: Location{File: "sample.go", Line: 49}},
+		{Name: ".Sync", Kind: callDropped, Location: Location{File: "sample.go", Line: 50}},
+		{Name: "Close", Kind: callDropped, Location: Location{File: "sample.go", Line: 51}},
+		{Name: "os.Remove", Kind: callDropped, Location: Location{File: "sample.go", Line: 52}},
+		{Name: "encoding/json.Unmarshal", Kind: callIgnored, Location: Location{File: "sample.go", Line: 53}},
+	}, callStmts)
+
+	errorFuncSet := knownErrorFuncs([]FileResults{{Package: "example.com/sample", ErrorFuncs: errorFuncs}})
+	// Close() is not the Close method, and Sync is not known to return an error
+	assert.Equal(t, 2, countErrorCalls(callStmts, callDropped, "example.com/sample", errorFuncSet))
+	// count() does not return an error, and Read is not known to return one
+	assert.Equal(t, 2, countErrorCalls(callStmts, callIgnored, "example.com/sample", errorFuncSet))
+	// The funcs of another package are not the ones of this package
+	assert.Equal(t, 1, countErrorCalls(callStmts, callDropped, "example.com/other", errorFuncSet))
+	assert.Equal(t, 50.0, e.Percent())
+}
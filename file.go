@@ -2,9 +2,15 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"io"
 	"os"
+	"path/filepath"
 )
 
 func processFile(dirPath, fileName string, config fileConfig) (Results, error) {
@@ -18,6 +24,10 @@ func processFile(dirPath, fileName string, config fileConfig) (Results, error) {
 	// Construct file path
 	filePath := joinPath(dirPath, fileName)
 
+	if config.gofmt {
+		return processFormattedFile(filePath, config)
+	}
+
 	// Open File
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -30,7 +40,7 @@ func processFile(dirPath, fileName string, config fileConfig) (Results, error) {
 		return r, err
 	}
 
-	r, err = processBufReader(bufReader)
+	r, err = processBufReader(bufReader, config.thresholds.maxNestingDepth)
 	if err != nil {
 		return r, fmt.Errorf("file %s: %s", file.Name(), err)
 	}
@@ -42,10 +52,117 @@ func processFile(dirPath, fileName string, config fileConfig) (Results, error) {
 	}
 
 	r.MaxCurlyBracesDepthLocation.File = filePath
+	for i := range r.DeepNesting {
+		r.DeepNesting[i].File = filePath
+	}
 
 	return r, nil
 }
 
+// processFormattedFile processes the file as it would be formatted by gofmt, so that the line counts do not depend on the
+// formatting. The line numbers in the Results refer to the formatted file.
+func processFormattedFile(filePath string, config fileConfig) (Results, error) {
+	var r Results
+
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return r, err
+	}
+
+	formatted := formatSource(src)
+
+	r, err = processBufReader(bufio.NewReader(bytes.NewReader(formatted)), config.thresholds.maxNestingDepth)
+	if err != nil {
+		return r, fmt.Errorf("file %s: %s", filePath, err)
+	}
+
+	if !bytes.Equal(src, formatted) {
+		r.NumOfUnformattedFiles = 1
+	}
+
+	r.MaxCurlyBracesDepthLocation.File = filePath
+	for i := range r.DeepNesting {
+		r.DeepNesting[i].File = filePath
+	}
+
+	return r, nil
+}
+
+// analyzeFile parses the Go file at filePath, and runs the analysis on it that needs more than line by line processing
+func analyzeFile(filePath string, r Results, config fileConfig) (FileResults, error) {
+	fr := FileResults{
+		Path:    filePath,
+		Module:  config.module.path,
+		Package: config.module.importPath(filepath.Dir(filePath)),
+		Results: r,
+	}
+
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return fr, err
+	}
+	// The line numbers in the Results refer to the formatted file, so that is the one to analyze
+	if config.gofmt {
+		src = formatSource(src)
+	}
+
+	// A file that does not parse keeps its line counts, but cannot be analyzed any further
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		fr.Warnings = append(fr.Warnings, fmt.Sprintf("skipped the analysis of a file that does not parse: %s", err))
+		return fr, nil
+	}
+
+	fr.PackageName = file.Name.Name
+	fr.LogicalStatements = countStatements(file)
+	fr.Generated = ast.IsGenerated(file)
+	fr.Declarations = countDeclarations(file)
+	fr.Comments = classifyComments(fset, file, src, filePath)
+	fr.Directives = findDirectives(fset, file, filePath)
+	fr.Imports = findImports(fset, file, filePath, config.module.path)
+	fr.ErrorHandling, fr.ErrorFuncs, fr.CallStatements = analyzeErrorHandling(fset, file, filePath)
+	fr.Concurrency = countConcurrency(file, importNames(file))
+	fr.DeferPanic = analyzeDeferPanic(fset, file, filePath)
+	fr.Generics, fr.GenericNames, fr.GenericRefs = analyzeGenerics(fset, file, filePath)
+	fr.Shapes = analyzeShapes(fset, file, filePath)
+	// Test and generated files are not part of the documented API
+	if !fr.Generated && !isTestFile(filePath) {
+		fr.DocCoverage = checkDocCoverage(fset, file, filePath)
+	}
+	if isTestFile(filePath) {
+		fr.Tests = analyzeTests(fset, file, filePath)
+		fr.TestCalls = findTestCalls(file)
+	}
+
+	fr.Funcs, err = analyzeFuncs(fset, file, src, filePath)
+	if err != nil {
+		return fr, fmt.Errorf("file %s: %s", filePath, err)
+	}
+	fr.Signatures = signaturesOf(fr.Funcs)
+	fr.Maintainability = maintainabilityOf(fr.Funcs)
+	fr.Fragments = findFragments(fset, file, src, filePath)
+
+	for i, loc := range fr.DeepNesting {
+		fr.DeepNesting[i].Function = enclosingFuncName(fset, file, loc.Line)
+	}
+
+	fr.Violations = checkThresholds(fset, file, fr, config.thresholds)
+	fr.Violations = append(fr.Violations, checkDefers(fr.DeferPanic)...)
+
+	return fr, nil
+}
+
+// formatSource returns the source as formatted by gofmt. A file that gofmt cannot format, because it does not parse, is
+// returned as it is.
+func formatSource(src []byte) []byte {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return src
+	}
+	return formatted
+}
+
 func shouldIncludeFile(dirPath, fileName string, config fileConfig) bool {
 	// Ignore non-Go files
 	if len(fileName) < 3 || fileName[len(fileName)-3:] != ".go" {
@@ -53,7 +170,7 @@ func shouldIncludeFile(dirPath, fileName string, config fileConfig) bool {
 	}
 
 	// Ignore test files
-	if config.ignoreTestFiles && len(fileName) > 8 && fileName[len(fileName)-8:] == "_test.go" {
+	if config.ignoreTestFiles && isTestFile(fileName) {
 		return false
 	}
 
@@ -66,6 +183,10 @@ func shouldIncludeFile(dirPath, fileName string, config fileConfig) bool {
 	return true
 }
 
+func isTestFile(fileName string) bool {
+	return len(fileName) > 8 && fileName[len(fileName)-8:] == "_test.go"
+}
+
 func getBufReader(file *os.File) (*bufio.Reader, error) {
 	stat, err := file.Stat()
 	if err != nil {
@@ -83,7 +204,9 @@ func getBufReader(file *os.File) (*bufio.Reader, error) {
 
 }
 
-func processBufReader(reader *bufio.Reader) (Results, error) {
+// processBufReader processes the reader line by line. Every place where the curly braces go deeper than maxDepth is
+// recorded in the Results, unless maxDepth is 0.
+func processBufReader(reader *bufio.Reader, maxDepth int) (Results, error) {
 	var r Results
 
 	r.NumOfFiles = 1
@@ -163,6 +286,15 @@ func processBufReader(reader *bufio.Reader) (Results, error) {
 			r.MaxCurlyBracesDepthLocation.Line = lineNum
 		}
 
+		if maxDepth > 0 && bracesDepth > maxDepth {
+			// Entering a deeply nested block, or going further down into one
+			if bracesDepth-lr.NumBracesDiff <= maxDepth {
+				r.DeepNesting = append(r.DeepNesting, NestingLocation{Location: Location{Line: lineNum}})
+			}
+			last := &r.DeepNesting[len(r.DeepNesting)-1]
+			last.Depth = maxInt(last.Depth, bracesDepth)
+		}
+
 		if errCheckPoint == 0 && lr.StartsErrCheck {
 			errCheckPoint = bracesDepth - 1
 			r.LinesOfErrCheck++
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,10 +12,11 @@ import (
 func TestProcessBufReader(t *testing.T) {
 
 	tests := []struct {
-		name    string
-		text    string
-		want    Results
-		wantErr bool
+		name     string
+		text     string
+		maxDepth int
+		want     Results
+		wantErr  bool
 	}{
 		{
 			name: "sample test file 1",
@@ -34,12 +36,39 @@ func TestProcessBufReader(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "sample test file 1 with max depth",
+			text:     sampleFileA,
+			maxDepth: 1,
+			want: Results{
+				NumOfFiles:          1,
+				LinesOfCode:         74,
+				LinesOfErrCheck:     3,
+				LinesOfComments:     4,
+				LinesWhitespace:     16,
+				TotalLinesProcessed: 96,
+				NumInlineComments:   0,
+				MaxCurlyBracesDepth: 2,
+				MaxCurlyBracesDepthLocation: Location{
+					Line: 16,
+					File: "",
+				},
+				DeepNesting: []NestingLocation{
+					{Location: Location{Line: 16}, Depth: 2},
+					{Location: Location{Line: 19}, Depth: 2},
+					{Location: Location{Line: 74}, Depth: 2},
+					{Location: Location{Line: 79}, Depth: 2},
+					{Location: Location{Line: 84}, Depth: 2},
+					{Location: Location{Line: 91}, Depth: 2},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			buff := bytes.NewBufferString(tt.text)
 			reader := bufio.NewReader(buff)
-			got, err := processBufReader(reader)
+			got, err := processBufReader(reader, tt.maxDepth)
 			assert.Equal(t, tt.wantErr, err != nil, "got error: %s", err)
 			assert.Equal(t, tt.want, got)
 
@@ -107,3 +136,83 @@ func TestShouldIncludeFile(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessFormattedFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		src  string
+		want Results
+	}{
+		{
+			name: "formatted",
+			src:  "package sample\n\nfunc f() {\n\treturn\n}\n",
+			want: Results{NumOfFiles: 1, LinesOfCode: 4, LinesWhitespace: 1, TotalLinesProcessed: 5, MaxCurlyBracesDepth: 1},
+		},
+		{
+			name: "not formatted",
+			src:  "package sample\n\n\n\nfunc f() {\n  return\n}\n",
+			want: Results{NumOfFiles: 1, NumOfUnformattedFiles: 1, LinesOfCode: 4, LinesWhitespace: 1, TotalLinesProcessed: 5, MaxCurlyBracesDepth: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := joinPath(dir, "sample.go")
+			err := os.WriteFile(filePath, []byte(tt.src), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := processFile(dir, "sample.go", fileConfig{gofmt: true})
+			assert.NoError(t, err)
+			tt.want.MaxCurlyBracesDepthLocation = Location{File: filePath, Line: 3}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProcessDirMalformedFile(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(joinPath(dir, "broken.go"), []byte("package sample\n\nfunc f() {\n\tif true {\n\t\treturn\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(joinPath(dir, "ok.go"), []byte("package sample\n\nfunc g() {}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := processDir(dir, fileConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Total.NumOfFiles)
+	assert.Equal(t, 8, report.Total.TotalLinesProcessed)
+
+	warnings := report.warnings()
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "skipped the analysis of a file that does not parse: "+joinPath(dir, "broken.go"))
+}
+
+func TestProcessDirFormatted(t *testing.T) {
+	dir := t.TempDir()
+	filePath := joinPath(dir, "sample.go")
+	err := os.WriteFile(filePath, []byte("package sample\n\n\n\nfunc f() {\n  if true {\n    return\n  }\n}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(joinPath(dir, "broken.go"), []byte("package sample\n\nfunc g() {\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := processDir(dir, fileConfig{gofmt: true, thresholds: thresholds{maxNestingDepth: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Total.NumOfUnformattedFiles)
+	assert.Equal(t, 1, len(report.warnings()))
+
+	// The lines of the nesting and of the func both refer to the formatted file
+	files := report.filesByPath()
+	assert.Equal(t, filePath, files[1].Path)
+	assert.Equal(t, []NestingLocation{{Location: Location{File: filePath, Line: 4}, Depth: 2, Function: "f"}}, files[1].DeepNesting)
+	assert.Equal(t, Location{File: filePath, Line: 3}, files[1].Funcs[0].Location)
+}
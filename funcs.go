@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// FuncStats represents the Results of a single function
+type FuncStats struct {
+	Name            string
+	Location        Location
+	LinesOfCode     int
+	LinesOfErrCheck int
+	Concurrency     Concurrency
+	Signature       Signature
+
+	Complexity           int // cyclomatic complexity
+	Halstead             Halstead
+	MaintainabilityIndex float64
+}
+
+// analyzeFuncs processes each function declared in the file the same way as a whole file is processed, so that the
+// lines of code and of err checks can be told apart per function. src is the content of the file.
+func analyzeFuncs(fset *token.FileSet, file *ast.File, src []byte, filePath string) ([]FuncStats, error) {
+	var funcs []FuncStats
+	pkgNames := importNames(file)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		start, end := fset.Position(fn.Pos()), fset.Position(fn.End())
+		text := append(src[start.Offset:end.Offset:end.Offset], '\n')
+		r, err := processBufReader(bufio.NewReader(bytes.NewReader(text)), 0)
+		if err != nil {
+			return funcs, fmt.Errorf("func %s: %s", funcName(fn), err)
+		}
+
+		f := FuncStats{
+			Name:            funcName(fn),
+			Location:        Location{File: filePath, Line: start.Line},
+			LinesOfCode:     r.LinesOfCode,
+			LinesOfErrCheck: r.LinesOfErrCheck,
+			Concurrency:     countConcurrency(fn, pkgNames),
+			Signature:       analyzeSignature(fn.Type),
+			Complexity:      cyclomaticComplexity(fn),
+			Halstead:        countHalstead(text),
+		}
+		f.MaintainabilityIndex = maintainabilityIndex(f.Halstead.Volume(), f.Complexity, f.LinesOfCode)
+		funcs = append(funcs, f)
+	}
+	return funcs, nil
+}
+
+// errCheckRatio is the share of non-comment, non-whitespace lines of the function that are spent on error checking
+func (f FuncStats) errCheckRatio() float64 {
+	return errCheckRatio(Results{LinesOfCode: f.LinesOfCode, LinesOfErrCheck: f.LinesOfErrCheck})
+}
+
+// funcs returns all the functions in the report
+func (r Report) funcs() []FuncStats {
+	var all []FuncStats
+	for _, f := range r.Files {
+		all = append(all, f.Funcs...)
+	}
+	return all
+}
+
+// mostBoilerplate returns the functions that have some error checking, the highest err-check ratio first. Ties are
+// sorted by the number of lines of error checking, and then by location.
+func mostBoilerplate(funcs []FuncStats) []FuncStats {
+	var ranked []FuncStats
+	for _, f := range funcs {
+		if f.LinesOfErrCheck > 0 {
+			ranked = append(ranked, f)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.errCheckRatio() != b.errCheckRatio() {
+			return a.errCheckRatio() > b.errCheckRatio()
+		}
+		if a.LinesOfErrCheck != b.LinesOfErrCheck {
+			return a.LinesOfErrCheck > b.LinesOfErrCheck
+		}
+		return locationLess(a.Location, b.Location)
+	})
+	return ranked
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeFuncs(t *testing.T) {
+	src := `package sample
+
+// open opens the file
+func open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+type File struct{}
+
+func (f *File) Close() error {
+	return nil
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funcs, err := analyzeFuncs(fset, file, []byte(src), "sample.go")
+	assert.NoError(t, err)
+
+	// Only check the line counts, the other metrics are tested on their own
+	var got []FuncStats
+	for _, f := range funcs {
+		got = append(got, FuncStats{Name: f.Name, Location: f.Location, LinesOfCode: f.LinesOfCode, LinesOfErrCheck: f.LinesOfErrCheck})
+	}
+	assert.Equal(t, []FuncStats{
+		{Name: "open", Location: Location{File: "sample.go", Line: 4}, LinesOfCode: 5, LinesOfErrCheck: 3},
+		{Name: "File.Close", Location: Location{File: "sample.go", Line: 15}, LinesOfCode: 3},
+	}, got)
+}
+
+func TestMostBoilerplate(t *testing.T) {
+	tests := []struct {
+		name  string
+		funcs []FuncStats
+		want  []string
+	}{
+		{
+			name:  "no funcs",
+			funcs: nil,
+			want:  nil,
+		},
+		{
+			name: "highest ratio first",
+			funcs: []FuncStats{
+				{Name: "a", Location: Location{File: "a.go", Line: 1}, LinesOfCode: 10, LinesOfErrCheck: 3},
+				{Name: "b", Location: Location{File: "a.go", Line: 20}, LinesOfCode: 2, LinesOfErrCheck: 6},
+				{Name: "c", Location: Location{File: "a.go", Line: 30}, LinesOfCode: 10},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "ties by err-check lines, then location",
+			funcs: []FuncStats{
+				{Name: "a", Location: Location{File: "b.go", Line: 1}, LinesOfCode: 3, LinesOfErrCheck: 3},
+				{Name: "b", Location: Location{File: "a.go", Line: 9}, LinesOfCode: 3, LinesOfErrCheck: 3},
+				{Name: "c", Location: Location{File: "a.go", Line: 1}, LinesOfCode: 6, LinesOfErrCheck: 6},
+			},
+			want: []string{"c", "b", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, f := range mostBoilerplate(tt.funcs) {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Generics counts the use of type parameters in a unit of code. Instantiations only knows about the generic funcs and
+// types of the project, and can only be counted once all the files have been seen.
+type Generics struct {
+	Funcs          int // func Map[T, U any](...)
+	Types          int // type Set[T comparable] ...
+	TypeParams     int // T and U in func Map[T, U any](...)
+	Constraints    int // type Number interface{ ~int | ~float64 }
+	Instantiations int // Map(xs, f), Map[int, string](xs, f) or Set[string]{}
+}
+
+// analyzeGenerics counts the generic declarations of the file. It also returns the names of the generic funcs and
+// types, and the places where a func or type is called or instantiated, so that the instantiations of the generics of
+// the project can be counted once all the files have been seen.
+func analyzeGenerics(fset *token.FileSet, file *ast.File, filePath string) (Generics, []string, []Symbol) {
+	var g Generics
+	var genericNames []string
+	var refs []Symbol
+
+	ref := func(name string, pos token.Pos) {
+		refs = append(refs, Symbol{
+			Name:     name,
+			Kind:     "instantiation",
+			Location: Location{File: filePath, Line: fset.Position(pos).Line},
+		})
+	}
+	inspect := func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			// Explicit instantiations are counted as index expressions
+			if name := calledName(n.Fun); name != "" {
+				ref(name, n.Pos())
+			}
+		case *ast.IndexExpr:
+			if name := calledName(n.X); name != "" {
+				ref(name, n.Pos())
+			}
+		case *ast.IndexListExpr:
+			if name := calledName(n.X); name != "" {
+				ref(name, n.Pos())
+			}
+		}
+		return true
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Type.TypeParams != nil {
+				g.Funcs++
+				g.TypeParams += decl.Type.TypeParams.NumFields()
+				genericNames = append(genericNames, decl.Name.Name)
+			}
+			// The receiver of a method of a generic type is not an instantiation
+			ast.Inspect(decl.Type, inspect)
+			if decl.Body != nil {
+				ast.Inspect(decl.Body, inspect)
+			}
+
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if spec, ok := spec.(*ast.TypeSpec); ok {
+					if spec.TypeParams != nil {
+						g.Types++
+						g.TypeParams += spec.TypeParams.NumFields()
+						genericNames = append(genericNames, spec.Name.Name)
+					}
+					if iface, ok := spec.Type.(*ast.InterfaceType); ok && isConstraint(iface) {
+						g.Constraints++
+					}
+				}
+			}
+			ast.Inspect(decl, inspect)
+		}
+	}
+
+	return g, genericNames, refs
+}
+
+// isConstraint tells whether the interface has a type set, and so can only be used as a type constraint. Embedded
+// interfaces can't be told apart from other types by name alone, except for the predeclared ones.
+func isConstraint(iface *ast.InterfaceType) bool {
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		switch t := field.Type.(type) {
+		case *ast.BinaryExpr:
+			if t.Op == token.OR {
+				return true
+			}
+		case *ast.UnaryExpr:
+			if t.Op == token.TILDE {
+				return true
+			}
+		case *ast.Ident:
+			if predeclaredTypes[t.Name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// predeclaredTypes are the predeclared types that are not interfaces
+var predeclaredTypes = map[string]bool{
+	"bool": true, "byte": true, "complex64": true, "complex128": true, "float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+// countInstantiations counts the references to the generic funcs and types of the project
+func countInstantiations(refs []Symbol, genericNames map[string]bool) int {
+	var n int
+	for _, ref := range refs {
+		if genericNames[ref.Name] {
+			n++
+		}
+	}
+	return n
+}
+
+func addGenerics(a, b Generics) Generics {
+	var g Generics
+	g.Funcs = a.Funcs + b.Funcs
+	g.Types = a.Types + b.Types
+	g.TypeParams = a.TypeParams + b.TypeParams
+	g.Constraints = a.Constraints + b.Constraints
+	g.Instantiations = a.Instantiations + b.Instantiations
+	return g
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeGenerics(t *testing.T) {
+	src := `package sample
+
+type Number interface {
+	~int | ~float64
+}
+
+type Integer interface {
+	int
+}
+
+type Stringer interface {
+	String() string
+}
+
+type Set[T comparable] map[T]struct{}
+
+func (s Set[T]) Add(v T) {
+	s[v] = struct{}{}
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func Map[T, U any](xs []T, f func(T) U) []U {
+	var ys []U
+	for _, x := range xs {
+		ys = append(ys, f(x))
+	}
+	return ys
+}
+
+func Sum[T Number](xs []T) T {
+	var sum T
+	for i := range xs {
+		sum += xs[i]
+	}
+	return sum
+}
+
+var pairs = []Pair[string, int]{}
+
+func main() {
+	s := Set[string]{}
+	s.Add("a")
+	Map[int, string]([]int{1}, strconv.Itoa)
+	Sum([]int{1, 2})
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, genericNames, refs := analyzeGenerics(fset, file, "sample.go")
+	assert.Equal(t, Generics{
+		Funcs:       2,
+		Types:       2,
+		TypeParams:  6,
+		Constraints: 2,
+	}, g)
+	assert.Equal(t, []string{"Set", "Pair", "Map", "Sum"}, genericNames)
+
+	var names = make(map[string]bool)
+	for _, name := range genericNames {
+		names[name] = true
+	}
+	// Pair[string, int], Set[string], Map[int, string] and Sum(...)
+	assert.Equal(t, 4, countInstantiations(refs, names))
+}
+
+func TestIsConstraint(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{name: "methods", src: "interface{ String() string }", want: false},
+		{name: "embedded interface", src: "interface{ fmt.Stringer }", want: false},
+		{name: "union", src: "interface{ int | string }", want: true},
+		{name: "tilde", src: "interface{ ~string }", want: true},
+		{name: "predeclared type", src: "interface{ String() string; string }", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			iface, ok := expr.(*ast.InterfaceType)
+			if !ok {
+				t.Fatalf("%s is not an interface", tt.src)
+			}
+			assert.Equal(t, tt.want, isConstraint(iface))
+		})
+	}
+}
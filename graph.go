@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// commandGraph is the sub command that exports the package import graph
+const commandGraph = "graph"
+
+// Supported output formats of the graph
+const (
+	formatDOT  = "dot"
+	formatJSON = "json"
+)
+
+// Graph is the import graph of the packages of a module
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a package in the Graph, weighted by its lines of code
+type GraphNode struct {
+	Package string `json:"package"`
+	Dir     string `json:"dir"`
+	Weight  int    `json:"weight"`
+}
+
+// GraphEdge is an import of one package in the Graph by another
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// buildGraph builds the import graph of the packages in the report. Only imports between the packages that were
+// processed are part of the graph.
+func buildGraph(report Report) Graph {
+	var g = Graph{
+		Nodes: []GraphNode{},
+		Edges: []GraphEdge{},
+	}
+
+	pkgs := report.packages()
+	var isNode = make(map[string]bool)
+	for _, p := range pkgs {
+		isNode[p.ImportPath] = true
+		g.Nodes = append(g.Nodes, GraphNode{
+			Package: p.ImportPath,
+			Dir:     p.Path,
+			Weight:  p.LinesOfCode,
+		})
+	}
+
+	for _, p := range pkgs {
+		for _, imp := range distinctImports(p.ImportPath, p.Imports) {
+			if imp.Kind == importInternal && isNode[imp.Path] {
+				g.Edges = append(g.Edges, GraphEdge{From: p.ImportPath, To: imp.Path})
+			}
+		}
+	}
+
+	return g
+}
+
+// writeGraph writes the import graph of the packages in the report as Graphviz DOT or JSON
+func writeGraph(w io.Writer, format string, report Report) error {
+	g := buildGraph(report)
+
+	switch format {
+	case formatDOT:
+		return writeGraphDOT(w, g)
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	default:
+		return fmt.Errorf("unsupported graph format '%s'", format)
+	}
+}
+
+// Font sizes of the nodes in the DOT output, for the smallest and the largest package
+const (
+	minNodeFontSize = 10
+	maxNodeFontSize = 30
+)
+
+// writeGraphDOT writes the graph in the DOT format of Graphviz. The font size of each node, and so the size of its box,
+// grows with the lines of code of the package.
+func writeGraphDOT(w io.Writer, g Graph) error {
+	var buf bytes.Buffer
+
+	var maxWeight int
+	for _, n := range g.Nodes {
+		maxWeight = maxInt(maxWeight, n.Weight)
+	}
+
+	buf.WriteString("digraph gloc {\n")
+	buf.WriteString("\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		label := fmt.Sprintf("%s\n%d lines of code", n.Package, n.Weight)
+		fontSize := minNodeFontSize
+		if maxWeight > 0 {
+			fontSize += (maxNodeFontSize - minNodeFontSize) * n.Weight / maxWeight
+		}
+		fmt.Fprintf(&buf, "\t%s [label=%s, fontsize=%d];\n", strconv.Quote(n.Package), strconv.Quote(label), fontSize)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&buf, "\t%s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To))
+	}
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
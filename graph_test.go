@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteGraph(t *testing.T) {
+	var sampleReport = Report{
+		Files: []FileResults{
+			{
+				Path:    "main.go",
+				Package: "example.com/app",
+				Results: Results{NumOfFiles: 1, LinesOfCode: 40},
+				Imports: []Import{
+					{Path: "example.com/app/store", Kind: importInternal},
+					{Path: "example.com/app/vendored", Kind: importInternal}, // not processed, so not in the graph
+					{Path: "fmt", Kind: importStd},
+				},
+			},
+			{
+				Path:    "store/store.go",
+				Package: "example.com/app/store",
+				Results: Results{NumOfFiles: 1, LinesOfCode: 120},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "dot",
+			format: formatDOT,
+			want: "digraph gloc {\n" +
+				"\tnode [shape=box];\n" +
+				"\t\"example.com/app\" [label=\"example.com/app\\n40 lines of code\", fontsize=16];\n" +
+				"\t\"example.com/app/store\" [label=\"example.com/app/store\\n120 lines of code\", fontsize=30];\n" +
+				"\t\"example.com/app\" -> \"example.com/app/store\";\n" +
+				"}\n",
+		},
+		{
+			name:   "json",
+			format: formatJSON,
+			want: `{
+  "nodes": [
+    {
+      "package": "example.com/app",
+      "dir": ".",
+      "weight": 40
+    },
+    {
+      "package": "example.com/app/store",
+      "dir": "store",
+      "weight": 120
+    }
+  ],
+  "edges": [
+    {
+      "from": "example.com/app",
+      "to": "example.com/app/store"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "unsupported format",
+			format:  formatText,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeGraph(&buf, tt.format, sampleReport)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of imports
+const (
+	importStd        = "std"
+	importInternal   = "internal"
+	importThirdParty = "third-party"
+)
+
+// Import is a single import of a package in a file
+type Import struct {
+	Path     string
+	Kind     string
+	Location Location
+}
+
+// ImportStats summarizes the imports of a single package
+type ImportStats struct {
+	Package    string
+	Std        int
+	Internal   int
+	ThirdParty int
+	FanOut     int // number of distinct packages imported
+	FanIn      int // number of packages of the project that import this one
+}
+
+// findImports lists the imports of the file. Imports are internal if they are part of the given module.
+func findImports(fset *token.FileSet, file *ast.File, filePath, module string) []Import {
+	var imports []Import
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		imports = append(imports, Import{
+			Path: path,
+			Kind: importKind(path, module),
+			Location: Location{
+				File: filePath,
+				Line: fset.Position(spec.Pos()).Line,
+			},
+		})
+	}
+	return imports
+}
+
+func importKind(path, module string) string {
+	if module != "" && (path == module || strings.HasPrefix(path, module+"/")) {
+		return importInternal
+	}
+	// Standard library packages don't have a domain name as their first element
+	first := strings.SplitN(path, "/", 2)[0]
+	if !strings.Contains(first, ".") {
+		return importStd
+	}
+	return importThirdParty
+}
+
+// distinctImports returns one Import per imported path (the first one seen), sorted by path. Imports of the package
+// itself, e.g. from an external test package, are left out.
+func distinctImports(pkgPath string, imports []Import) []Import {
+	var seen = make(map[string]bool)
+	var distinct []Import
+	for _, imp := range imports {
+		if seen[imp.Path] || imp.Path == pkgPath {
+			continue
+		}
+		seen[imp.Path] = true
+		distinct = append(distinct, imp)
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		return distinct[i].Path < distinct[j].Path
+	})
+	return distinct
+}
+
+// importStats computes the import statistics of each package. Fan-in only counts packages that are part of pkgs.
+func importStats(pkgs []PackageResults) []ImportStats {
+	var fanIn = make(map[string]int)
+	for _, p := range pkgs {
+		for _, imp := range distinctImports(p.ImportPath, p.Imports) {
+			fanIn[imp.Path]++
+		}
+	}
+
+	var stats []ImportStats
+	for _, p := range pkgs {
+		s := ImportStats{
+			Package: p.ImportPath,
+			FanIn:   fanIn[p.ImportPath],
+		}
+		for _, imp := range distinctImports(p.ImportPath, p.Imports) {
+			s.FanOut++
+			switch imp.Kind {
+			case importStd:
+				s.Std++
+			case importInternal:
+				s.Internal++
+			case importThirdParty:
+				s.ThirdParty++
+			}
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// mostImported counts the number of packages that import each path, the most imported first
+func mostImported(pkgs []PackageResults) []namedCount {
+	var counts = make(map[string]int)
+	for _, p := range pkgs {
+		for _, imp := range distinctImports(p.ImportPath, p.Imports) {
+			counts[imp.Path]++
+		}
+	}
+	return sortedCounts(counts)
+}
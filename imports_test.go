@@ -0,0 +1,57 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindImports(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "config.go", sampleFileA, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := findImports(fset, file, "config.go", "github.com/teejays/clog")
+	assert.Equal(t, []Import{
+		{Path: "fmt", Kind: importStd, Location: Location{File: "config.go", Line: 4}},
+		{Path: "strings", Kind: importStd, Location: Location{File: "config.go", Line: 5}},
+		{Path: "github.com/BurntSushi/toml", Kind: importThirdParty, Location: Location{File: "config.go", Line: 7}},
+		{Path: "github.com/teejays/clog", Kind: importInternal, Location: Location{File: "config.go", Line: 8}},
+	}, got)
+}
+
+func TestImportStats(t *testing.T) {
+	pkgs := []PackageResults{
+		{
+			ImportPath: "example.com/app",
+			Imports: []Import{
+				{Path: "example.com/app/store", Kind: importInternal},
+				{Path: "fmt", Kind: importStd},
+				{Path: "fmt", Kind: importStd},
+				{Path: "github.com/teejays/clog", Kind: importThirdParty},
+			},
+		},
+		{
+			ImportPath: "example.com/app/store",
+			Imports: []Import{
+				{Path: "fmt", Kind: importStd},
+				{Path: "example.com/app/store", Kind: importInternal}, // from an external test package
+			},
+		},
+	}
+
+	assert.Equal(t, []ImportStats{
+		{Package: "example.com/app", Std: 1, Internal: 1, ThirdParty: 1, FanOut: 3, FanIn: 0},
+		{Package: "example.com/app/store", Std: 1, FanOut: 1, FanIn: 1},
+	}, importStats(pkgs))
+
+	assert.Equal(t, []namedCount{
+		{"fmt", 2},
+		{"example.com/app/store", 1},
+		{"github.com/teejays/clog", 1},
+	}, mostImported(pkgs))
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rules that are checked against the imports of the project
+const (
+	ruleForbiddenImport = "forbidden-import"
+	ruleImportCycle     = "import-cycle"
+)
+
+// importRule forbids the packages matching from to import the packages matching to
+type importRule struct {
+	from string
+	to   string
+}
+
+// parseImportRules parses rules written as from:to pairs, separated by commas (e.g. "domain:transport,domain:storage")
+func parseImportRules(s string) ([]importRule, error) {
+	var rules []importRule
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pair := strings.Split(part, ":")
+		if len(pair) != 2 || strings.TrimSpace(pair[0]) == "" || strings.TrimSpace(pair[1]) == "" {
+			return nil, fmt.Errorf("invalid import rule '%s', should be like 'from:to'", part)
+		}
+		rules = append(rules, importRule{
+			from: strings.TrimSpace(pair[0]),
+			to:   strings.TrimSpace(pair[1]),
+		})
+	}
+	return rules, nil
+}
+
+// matchesPackage tells whether the pattern matches the package or one of its parents, as whole elements of the path
+// relative to the module. For example in the module example.com/app, "domain" matches "example.com/app/domain" and
+// "example.com/app/domain/user", but not "example.com/app/subdomain", and "app" matches none of them.
+func matchesPackage(pkgPath, module, pattern string) bool {
+	if module != "" {
+		if pkgPath == module {
+			return false
+		}
+		pkgPath = strings.TrimPrefix(pkgPath, module+"/")
+	}
+	pkgPath, pattern = "/"+pkgPath+"/", "/"+strings.Trim(pattern, "/")+"/"
+	return strings.Contains(pkgPath, pattern)
+}
+
+// checkImportRules reports every import in the project that breaks one of the rules
+func checkImportRules(report Report, rules []importRule) []Violation {
+	var violations []Violation
+	for _, f := range report.Files {
+		for _, rule := range rules {
+			if !matchesPackage(f.Package, f.Module, rule.from) {
+				continue
+			}
+			for _, imp := range f.Imports {
+				if imp.Kind != importInternal || !matchesPackage(imp.Path, f.Module, rule.to) {
+					continue
+				}
+				violations = append(violations, Violation{
+					Rule:    ruleForbiddenImport,
+					Message: fmt.Sprintf("package %s imports %s, but %s must not import %s", f.Package, imp.Path, rule.from, rule.to),
+					File:    imp.Location.File,
+					Region:  Region{StartLine: imp.Location.Line, EndLine: imp.Location.Line},
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// checkImportCycles reports every import between the packages of the project that is part of an import cycle. Files
+// of external test packages (package x_test) are left out, as they are allowed to import what imports their package.
+func checkImportCycles(report Report) []Violation {
+	// Edges of the import graph, along with the imports that make them
+	var edges = make(map[string]map[string][]Import)
+	for _, f := range report.Files {
+		if strings.HasSuffix(f.PackageName, "_test") {
+			continue
+		}
+		if edges[f.Package] == nil {
+			edges[f.Package] = make(map[string][]Import)
+		}
+		for _, imp := range f.Imports {
+			if imp.Kind == importInternal && imp.Path != f.Package {
+				edges[f.Package][imp.Path] = append(edges[f.Package][imp.Path], imp)
+			}
+		}
+	}
+
+	var violations []Violation
+	for _, component := range stronglyConnected(edges) {
+		if len(component) < 2 {
+			continue
+		}
+		var inComponent = make(map[string]bool)
+		for _, pkg := range component {
+			inComponent[pkg] = true
+		}
+		for _, from := range component {
+			for _, to := range sortedKeys(edges[from]) {
+				if !inComponent[to] {
+					continue
+				}
+				cycle := append([]string{from}, shortestPath(edges, to, from, inComponent)...)
+				for _, imp := range edges[from][to] {
+					violations = append(violations, Violation{
+						Rule:    ruleImportCycle,
+						Message: fmt.Sprintf("import cycle: %s", strings.Join(cycle, " -> ")),
+						File:    imp.Location.File,
+						Region:  Region{StartLine: imp.Location.Line, EndLine: imp.Location.Line},
+					})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// stronglyConnected returns the strongly connected components of the graph (Tarjan's algorithm). Each component is
+// sorted, and so are the components by their first package.
+func stronglyConnected(edges map[string]map[string][]Import) [][]string {
+	var index = make(map[string]int)
+	var lowLink = make(map[string]int)
+	var onStack = make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var visit func(pkg string)
+	visit = func(pkg string) {
+		index[pkg] = len(index)
+		lowLink[pkg] = index[pkg]
+		stack = append(stack, pkg)
+		onStack[pkg] = true
+
+		for _, next := range sortedKeys(edges[pkg]) {
+			if _, seen := index[next]; !seen {
+				visit(next)
+				lowLink[pkg] = minInt(lowLink[pkg], lowLink[next])
+			} else if onStack[next] {
+				lowLink[pkg] = minInt(lowLink[pkg], index[next])
+			}
+		}
+
+		if lowLink[pkg] == index[pkg] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == pkg {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, pkg := range sortedKeys(edges) {
+		if _, seen := index[pkg]; !seen {
+			visit(pkg)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+// shortestPath finds the shortest path of imports from one package to another, only going through the allowed
+// packages. The returned path starts at from and ends at to.
+func shortestPath(edges map[string]map[string][]Import, from, to string, allowed map[string]bool) []string {
+	var prev = map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if pkg == to {
+			break
+		}
+		for _, next := range sortedKeys(edges[pkg]) {
+			if _, seen := prev[next]; seen || !allowed[next] {
+				continue
+			}
+			prev[next] = pkg
+			queue = append(queue, next)
+		}
+	}
+
+	var path []string
+	for pkg := to; pkg != ""; pkg = prev[pkg] {
+		path = append([]string{pkg}, path...)
+	}
+	return path
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImportRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []importRule
+		wantErr bool
+	}{
+		{
+			name: "no rules",
+			s:    "",
+		},
+		{
+			name: "many rules",
+			s:    "domain:transport, domain:storage",
+			want: []importRule{
+				{from: "domain", to: "transport"},
+				{from: "domain", to: "storage"},
+			},
+		},
+		{
+			name:    "invalid rule",
+			s:       "domain",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseImportRules(tt.s)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchesPackage(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgPath string
+		module  string
+		pattern string
+		want    bool
+	}{
+		{"same package", "example.com/app/domain", "example.com/app", "domain", true},
+		{"sub package", "example.com/app/domain/user", "example.com/app", "domain", true},
+		{"nested pattern", "example.com/app/internal/domain", "example.com/app", "internal/domain", true},
+		{"partial element", "example.com/app/subdomain", "example.com/app", "domain", false},
+		{"element of the module path", "github.com/domain/app/transport", "github.com/domain/app", "domain", false},
+		{"root package of the module", "github.com/domain/app", "github.com/domain/app", "app", false},
+		{"no module", "internal/domain", "", "domain", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesPackage(tt.pkgPath, tt.module, tt.pattern))
+		})
+	}
+}
+
+func TestCheckImports(t *testing.T) {
+	var sampleReport = Report{
+		Files: []FileResults{
+			{
+				Path:        "domain/user.go",
+				Module:      "example.com/app",
+				Package:     "example.com/app/domain",
+				PackageName: "domain",
+				Imports: []Import{
+					{Path: "example.com/app/transport", Kind: importInternal, Location: Location{File: "domain/user.go", Line: 4}},
+				},
+			},
+			{
+				Path:        "transport/http.go",
+				Module:      "example.com/app",
+				Package:     "example.com/app/transport",
+				PackageName: "transport",
+				Imports: []Import{
+					{Path: "net/http", Kind: importStd, Location: Location{File: "transport/http.go", Line: 4}},
+					{Path: "example.com/app/store", Kind: importInternal, Location: Location{File: "transport/http.go", Line: 6}},
+				},
+			},
+			{
+				Path:        "store/store.go",
+				Module:      "example.com/app",
+				Package:     "example.com/app/store",
+				PackageName: "store",
+				Imports: []Import{
+					{Path: "example.com/app/domain", Kind: importInternal, Location: Location{File: "store/store.go", Line: 3}},
+				},
+			},
+			{
+				Path:        "domain/user_test.go",
+				Module:      "example.com/app",
+				Package:     "example.com/app/domain",
+				PackageName: "domain_test",
+				Imports: []Import{
+					{Path: "example.com/app/domain", Kind: importInternal, Location: Location{File: "domain/user_test.go", Line: 3}},
+				},
+			},
+		},
+	}
+
+	rules := []importRule{{from: "domain", to: "transport"}}
+	assert.Equal(t, []Violation{
+		{
+			Rule:    ruleForbiddenImport,
+			Message: "package example.com/app/domain imports example.com/app/transport, but domain must not import transport",
+			File:    "domain/user.go",
+			Region:  Region{StartLine: 4, EndLine: 4},
+		},
+	}, checkImportRules(sampleReport, rules))
+
+	assert.Equal(t, []Violation{
+		{
+			Rule:    ruleImportCycle,
+			Message: "import cycle: example.com/app/domain -> example.com/app/transport -> example.com/app/store -> example.com/app/domain",
+			File:    "domain/user.go",
+			Region:  Region{StartLine: 4, EndLine: 4},
+		},
+		{
+			Rule:    ruleImportCycle,
+			Message: "import cycle: example.com/app/store -> example.com/app/domain -> example.com/app/transport -> example.com/app/store",
+			File:    "store/store.go",
+			Region:  Region{StartLine: 3, EndLine: 3},
+		},
+		{
+			Rule:    ruleImportCycle,
+			Message: "import cycle: example.com/app/transport -> example.com/app/store -> example.com/app/domain -> example.com/app/transport",
+			File:    "transport/http.go",
+			Region:  Region{StartLine: 6, EndLine: 6},
+		},
+	}, checkImportCycles(sampleReport))
+}
@@ -11,10 +11,21 @@ import (
 
 // Args can be passed as the command line arguments, and control the program
 type Args struct {
-	rootPath        string
-	excludeDirs     string
-	excludeFiles    string
-	ignoreTestFiles bool
+	rootPath            string
+	excludeDirs         string
+	excludeFiles        string
+	ignoreTestFiles     bool
+	format              string
+	color               bool
+	sections            string
+	basePath            string
+	maxFuncLines        int
+	maxDepth            int
+	maxFileLines        int
+	maxInterfaceMethods int
+	maxParams           int
+	forbidImports       string
+	gofmt               bool
 }
 
 func main() {
@@ -26,31 +37,121 @@ func main() {
 }
 
 func run() error {
+	// Sub command, if any, comes before the flags
+	var command string
+	cmdArgs := os.Args[1:]
+	if len(cmdArgs) > 0 && cmdArgs[0] == commandGraph {
+		command, cmdArgs = cmdArgs[0], cmdArgs[1:]
+	}
+
+	defaultFormat, formatUsage := formatText, "output format (text, raw, markdown, sarif, openmetrics)"
+	if command == commandGraph {
+		defaultFormat, formatUsage = formatDOT, "output format of the graph (dot, json)"
+	}
+
 	// Parse Args
 	var args Args
 	flag.StringVar(&args.rootPath, "root", "", "path of the directory to analyze")
 	flag.BoolVar(&args.ignoreTestFiles, "ignore-test-files", true, "should be ignore test files (default to true)")
 	flag.StringVar(&args.excludeDirs, "exclude-dirs", "", "directories to be excluded (comma separated)")
 	flag.StringVar(&args.excludeFiles, "exclude-files", "", "files to be excluded (comma separated)")
-	flag.Parse()
+	flag.StringVar(&args.format, "format", defaultFormat, formatUsage)
+	flag.BoolVar(&args.color, "color", false, "should the text output be colored")
+	flag.StringVar(&args.sections, "sections", sectionAll, "sections of the text output to write after the totals (comma separated, or all): "+strings.Join(textSectionNames(), ", "))
+	flag.IntVar(&args.maxFuncLines, "max-func-lines", 0, "report functions longer than this many lines (0 disables the check)")
+	flag.IntVar(&args.maxDepth, "max-depth", 0, "report curly braces nested deeper than this (0 disables the check)")
+	flag.IntVar(&args.maxFileLines, "max-file-lines", 0, "report files longer than this many lines (0 disables the check)")
+	flag.IntVar(&args.maxInterfaceMethods, "max-interface-methods", 0, "report interfaces with more methods than this (0 disables the check)")
+	flag.IntVar(&args.maxParams, "max-params", 0, "report functions with more params than this (0 disables the check)")
+	flag.StringVar(&args.forbidImports, "forbid-imports", "", "layering rules, as packages that must not import others (e.g. domain:transport,domain:storage)")
+	flag.BoolVar(&args.gofmt, "gofmt", false, "count the lines of each file as formatted by gofmt, and list the files that are not")
+	flag.StringVar(&args.basePath, "base", "", "path of a checkout of the base revision to compare against (optional)")
+	err := flag.CommandLine.Parse(cmdArgs)
+	if err != nil {
+		return err
+	}
 
 	args.rootPath = strings.TrimSpace(args.rootPath)
 	if args.rootPath == "" {
 		return fmt.Errorf("directory is empty")
 	}
 
+	importRules, err := parseImportRules(args.forbidImports)
+	if err != nil {
+		return err
+	}
+
+	sections, err := parseSections(args.sections)
+	if err != nil {
+		return err
+	}
+
 	// Process the root project directory
 	config := fileConfig{
 		ignoreTestFiles: args.ignoreTestFiles,
+		gofmt:           args.gofmt,
 		excludeDirs:     strings.Split(args.excludeDirs, ","),
 		excludeFiles:    strings.Split(args.excludeFiles, ","),
+		thresholds: thresholds{
+			maxFuncLines:        args.maxFuncLines,
+			maxNestingDepth:     args.maxDepth,
+			maxFileLines:        args.maxFileLines,
+			maxInterfaceMethods: args.maxInterfaceMethods,
+			maxParams:           args.maxParams,
+		},
 	}
-	r, err := processDir(args.rootPath, config)
+	module, err := findModule(args.rootPath)
 	if err != nil {
 		return err
 	}
+	config.module = module
+	report, err := processDir(args.rootPath, config)
+	if err != nil {
+		return err
+	}
+	report = detectClones(report)
+	report = mapTests(report)
+	report.Violations = append(report.Violations, checkImportRules(report, importRules)...)
+	report.Violations = append(report.Violations, checkImportCycles(report)...)
+
+	if command == commandGraph {
+		return writeGraph(os.Stdout, args.format, report)
+	}
+
+	// Process the base revision, if we're comparing against one
+	var base *Report
+	args.basePath = strings.TrimSpace(args.basePath)
+	if args.basePath != "" {
+		baseConfig := config
+		baseConfig.module, err = findModule(args.basePath)
+		if err != nil {
+			return fmt.Errorf("base: %s", err)
+		}
+		baseReport, err := processDir(args.basePath, baseConfig)
+		if err != nil {
+			return fmt.Errorf("base: %s", err)
+		}
+		baseReport = detectClones(baseReport)
+		baseReport = mapTests(baseReport)
+		base = &baseReport
+	}
 
-	fmt.Printf("Results: \n%+v\n", r)
+	output := outputConfig{
+		format:   args.format,
+		color:    args.color,
+		sections: sections,
+	}
+	// The text output lists the warnings itself
+	if args.format != formatText {
+		for _, w := range report.warnings() {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+	}
+
+	err = writeReport(os.Stdout, output, report, base)
+	if err != nil {
+		return err
+	}
 
 	return nil
 
@@ -60,61 +161,81 @@ type fileConfig struct {
 	excludeDirs     []string
 	excludeFiles    []string
 	ignoreTestFiles bool
+	gofmt           bool // count the lines of the files as formatted by gofmt
+	thresholds      thresholds
+	module          goModule // the module of the directory being processed
 }
 
-func processDir(dirPath string, config fileConfig) (Results, error) {
-	var results Results
+func processDir(dirPath string, config fileConfig) (Report, error) {
+	var report Report
 
 	// Excluded dirs
 	if sliceContainsString(config.excludeDirs, dirPath) {
-		return results, nil
+		return report, nil
 	}
 
 	// Open the directory
 	clog.Debugf("Opening Dir: %s", dirPath)
 	dir, err := os.Open(dirPath)
 	if err != nil {
-		return results, err
+		return report, err
 	}
 
 	// Find whether the file is a dir or not.
 	dInfo, err := dir.Stat()
 	if err != nil {
-		return results, err
+		return report, err
 	}
 
 	if !dInfo.IsDir() {
-		return results, fmt.Errorf("%s is not a directory", dirPath)
+		return report, fmt.Errorf("%s is not a directory", dirPath)
 	}
 
 	// Get names of all files
 	subFiles, err := dir.Readdir(-1)
 	if err != nil {
-		return results, err
+		return report, err
+	}
+
+	// A go.mod file starts a new module for this dir and its sub dirs
+	module, ok, err := readModule(dirPath)
+	if err != nil {
+		return report, err
+	}
+	if ok {
+		config.module = module
 	}
 
 	for _, subFile := range subFiles {
 
 		// If Dir
 		if subFile.IsDir() {
-			r, err := processDir(joinPath(dirPath, subFile.Name()), config)
+			subReport, err := processDir(joinPath(dirPath, subFile.Name()), config)
 			if err != nil {
-				return results, err
+				return report, err
 			}
-			results = addResults(results, r)
+			report = addReports(report, subReport)
 		}
 
 		// If file
 		r, err := processFile(dirPath, subFile.Name(), config)
 		if err != nil {
-			return results, err
+			return report, err
 		}
 
-		results = addResults(results, r)
+		if r.NumOfFiles > 0 {
+			fr, err := analyzeFile(joinPath(dirPath, subFile.Name()), r, config)
+			if err != nil {
+				return report, err
+			}
+			r = fr.Results
+			report.Files = append(report.Files, fr)
+		}
+		report.Total = addResults(report.Total, r)
 
 	}
 
-	return results, nil
+	return report, nil
 
 }
 
@@ -144,3 +265,10 @@ func maxInt(arr ...int) int {
 	}
 	return max
 }
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
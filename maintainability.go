@@ -0,0 +1,184 @@
+package main
+
+import (
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"math"
+	"sort"
+)
+
+// Halstead counts the operators and operands in a unit of code. Keywords and punctuation are operators, identifiers
+// and literals are operands.
+type Halstead struct {
+	Operators         int
+	Operands          int
+	DistinctOperators int
+	DistinctOperands  int
+}
+
+// Volume is the size of the code in bits: its length times the log2 of its vocabulary
+func (h Halstead) Volume() float64 {
+	vocabulary := h.DistinctOperators + h.DistinctOperands
+	if vocabulary == 0 {
+		return 0
+	}
+	return float64(h.Operators+h.Operands) * math.Log2(float64(vocabulary))
+}
+
+// Difficulty tells how hard the code is to write or understand
+func (h Halstead) Difficulty() float64 {
+	if h.DistinctOperands == 0 {
+		return 0
+	}
+	return float64(h.DistinctOperators) / 2 * float64(h.Operands) / float64(h.DistinctOperands)
+}
+
+// Effort is the mental effort needed to write the code
+func (h Halstead) Effort() float64 {
+	return h.Difficulty() * h.Volume()
+}
+
+// countHalstead scans the Go source and counts its operators and operands. The semicolons that are automatically
+// inserted at the end of the lines are left out.
+func countHalstead(src []byte) Halstead {
+	var h Halstead
+	var operators = make(map[string]bool)
+	var operands = make(map[string]bool)
+
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", fset.Base(), len(src)), src, nil, 0)
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch {
+		case tok == token.SEMICOLON && lit == "\n":
+			continue
+		case tok.IsLiteral():
+			h.Operands++
+			operands[lit] = true
+		case tok.IsOperator(), tok.IsKeyword():
+			h.Operators++
+			operators[tok.String()] = true
+		}
+	}
+
+	h.DistinctOperators = len(operators)
+	h.DistinctOperands = len(operands)
+	return h
+}
+
+// cyclomaticComplexity is the number of independent paths through the func: one, plus one for each branch. Func
+// literals count as part of the func they are declared in.
+func cyclomaticComplexity(fn *ast.FuncDecl) int {
+	complexity := 1
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// maintainabilityIndex combines the Halstead volume, the cyclomatic complexity and the lines of code of a func into a
+// number between 0 (hard to maintain) and 100 (easy to maintain), as normalized by Visual Studio.
+func maintainabilityIndex(volume float64, complexity, linesOfCode int) float64 {
+	mi := 171 - 5.2*math.Log(math.Max(volume, 1)) - 0.23*float64(complexity) - 16.2*math.Log(math.Max(float64(linesOfCode), 1))
+	return math.Max(0, mi*100/171)
+}
+
+// Maintainability sums up the maintainability of the funcs in a unit of code
+type Maintainability struct {
+	Funcs         int
+	Complexity    int // sum of the cyclomatic complexity of the funcs
+	Volume        float64
+	Effort        float64
+	LinesOfCode   int     // lines of code of the funcs
+	WeightedIndex float64 // sum of the maintainability index of each func, times its lines of code
+}
+
+// Index is the average maintainability index of the funcs, weighted by their lines of code. Code without funcs is
+// fully maintainable.
+func (m Maintainability) Index() float64 {
+	if m.LinesOfCode == 0 {
+		return 100
+	}
+	return m.WeightedIndex / float64(m.LinesOfCode)
+}
+
+// maintainabilityOf sums up the maintainability of the funcs
+func maintainabilityOf(funcs []FuncStats) Maintainability {
+	var m Maintainability
+	for _, f := range funcs {
+		m.Funcs++
+		m.Complexity += f.Complexity
+		m.Volume += f.Halstead.Volume()
+		m.Effort += f.Halstead.Effort()
+		m.LinesOfCode += f.LinesOfCode
+		m.WeightedIndex += f.MaintainabilityIndex * float64(f.LinesOfCode)
+	}
+	return m
+}
+
+// maintainability sums up the maintainability of all the funcs in the report
+func (r Report) maintainability() Maintainability {
+	var m Maintainability
+	for _, f := range r.Files {
+		m = addMaintainability(m, f.Maintainability)
+	}
+	return m
+}
+
+// leastMaintainable returns the funcs sorted by their maintainability index, the lowest first
+func leastMaintainable(funcs []FuncStats) []FuncStats {
+	var ranked = append([]FuncStats(nil), funcs...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.MaintainabilityIndex != b.MaintainabilityIndex {
+			return a.MaintainabilityIndex < b.MaintainabilityIndex
+		}
+		return locationLess(a.Location, b.Location)
+	})
+	return ranked
+}
+
+// leastMaintainableFiles returns the files that have funcs, sorted by their maintainability index, the lowest first
+func leastMaintainableFiles(r Report) []FileResults {
+	var ranked []FileResults
+	for _, f := range r.sortedFiles(func(a, b FileResults) bool {
+		return a.Maintainability.Index() < b.Maintainability.Index()
+	}) {
+		if f.Maintainability.Funcs > 0 {
+			ranked = append(ranked, f)
+		}
+	}
+	return ranked
+}
+
+func addMaintainability(a, b Maintainability) Maintainability {
+	var m Maintainability
+	m.Funcs = a.Funcs + b.Funcs
+	m.Complexity = a.Complexity + b.Complexity
+	m.Volume = a.Volume + b.Volume
+	m.Effort = a.Effort + b.Effort
+	m.LinesOfCode = a.LinesOfCode + b.LinesOfCode
+	m.WeightedIndex = a.WeightedIndex + b.WeightedIndex
+	return m
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountHalstead(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Halstead
+	}{
+		{
+			name: "empty",
+			src:  "",
+			want: Halstead{},
+		},
+		{
+			// operators: = + ; operands: x y 1
+			name: "assignment",
+			src:  "x = y + 1\n",
+			want: Halstead{Operators: 2, Operands: 3, DistinctOperators: 2, DistinctOperands: 3},
+		},
+		{
+			// operators: if > { return } ; operands: x 0 x
+			name: "keywords and repeated operands",
+			src:  "if x > 0 {\n\treturn x\n}\n",
+			want: Halstead{Operators: 5, Operands: 3, DistinctOperators: 5, DistinctOperands: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, countHalstead([]byte(tt.src)))
+		})
+	}
+}
+
+func TestHalstead(t *testing.T) {
+	h := Halstead{Operators: 40, Operands: 30, DistinctOperators: 10, DistinctOperands: 6}
+	assert.Equal(t, 280.0, h.Volume())
+	assert.Equal(t, 25.0, h.Difficulty())
+	assert.Equal(t, 7000.0, h.Effort())
+
+	assert.Equal(t, 0.0, Halstead{}.Volume())
+	assert.Equal(t, 0.0, Halstead{}.Difficulty())
+}
+
+func TestCyclomaticComplexity(t *testing.T) {
+	src := `package sample
+
+func simple() int {
+	return 1
+}
+
+func branches(xs []int, ch chan int) int {
+	n := 0
+	for _, x := range xs {
+		if x > 0 && x < 10 || x == 100 {
+			n++
+		}
+	}
+	switch n {
+	case 1, 2:
+		n--
+	case 3:
+	default:
+	}
+	select {
+	case v := <-ch:
+		n += v
+	default:
+	}
+	for i := 0; i < n; i++ {
+	}
+	return n
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for _, decl := range file.Decls {
+		got = append(got, cyclomaticComplexity(decl.(*ast.FuncDecl)))
+	}
+	// branches: 1 + range + if + && + || + 2 cases + 1 comm clause + for
+	assert.Equal(t, []int{1, 9}, got)
+}
+
+func TestMaintainabilityIndex(t *testing.T) {
+	assert.Equal(t, 100.0, maintainabilityIndex(0, 0, 0))
+	assert.True(t, maintainabilityIndex(100, 1, 5) > maintainabilityIndex(1000, 10, 50))
+	assert.Equal(t, 0.0, maintainabilityIndex(1e12, 500, 100000))
+}
+
+func TestMaintainabilityOf(t *testing.T) {
+	m := maintainabilityOf([]FuncStats{
+		{LinesOfCode: 10, Complexity: 3, MaintainabilityIndex: 50},
+		{LinesOfCode: 30, Complexity: 1, MaintainabilityIndex: 90},
+	})
+	assert.Equal(t, 2, m.Funcs)
+	assert.Equal(t, 4, m.Complexity)
+	assert.Equal(t, 40, m.LinesOfCode)
+	assert.Equal(t, 80.0, m.Index())
+
+	assert.Equal(t, 100.0, Maintainability{}.Index())
+}
+
+func TestLeastMaintainableFiles(t *testing.T) {
+	report := Report{
+		Files: []FileResults{
+			{Path: "b.go", Maintainability: Maintainability{Funcs: 1, LinesOfCode: 10, WeightedIndex: 600}},
+			{Path: "empty.go"},
+			{Path: "c.go", Maintainability: Maintainability{Funcs: 2, LinesOfCode: 10, WeightedIndex: 400}},
+			{Path: "a.go", Maintainability: Maintainability{Funcs: 1, LinesOfCode: 10, WeightedIndex: 600}},
+		},
+	}
+
+	var paths []string
+	for _, f := range leastMaintainableFiles(report) {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{"c.go", "a.go", "b.go"}, paths)
+}
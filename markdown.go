@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// numTopOffenders is the number of files listed in each of the top offenders tables
+const numTopOffenders = 5
+
+// writeMarkdown writes a summary of the report as Markdown tables, suitable for pasting into a pull request. If base is
+// not nil, the totals table includes the +/- change against it.
+func writeMarkdown(w io.Writer, report Report, base *Report) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("### Gloc Summary\n\n")
+
+	// Totals
+	if base == nil {
+		buf.WriteString("| Metric | Value |\n")
+		buf.WriteString("| :--- | ---: |\n")
+	} else {
+		buf.WriteString("| Metric | Value | Change |\n")
+		buf.WriteString("| :--- | ---: | ---: |\n")
+	}
+	for _, m := range summaryMetrics {
+		fmt.Fprintf(&buf, "| %s | %d |", m.name, m.value(report.Total))
+		if base != nil {
+			fmt.Fprintf(&buf, " %s |", formatDelta(m.value(report.Total)-m.value(base.Total)))
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(report.Files) == 0 {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	// Maintainability
+	m := report.maintainability()
+	buf.WriteString("\n#### Maintainability\n\n")
+	buf.WriteString("| Metric | Value |\n")
+	buf.WriteString("| :--- | ---: |\n")
+	fmt.Fprintf(&buf, "| Maintainability Index | %.1f |\n", m.Index())
+	fmt.Fprintf(&buf, "| Cyclomatic Complexity | %d |\n", m.Complexity)
+	fmt.Fprintf(&buf, "| Halstead Volume | %.0f |\n", m.Volume)
+	fmt.Fprintf(&buf, "| Halstead Effort | %.0f |\n", m.Effort)
+
+	// Least maintainable files
+	buf.WriteString("\n#### Least Maintainable Files\n\n")
+	buf.WriteString("| File | Funcs | Maintainability Index |\n")
+	buf.WriteString("| :--- | ---: | ---: |\n")
+	for _, f := range topFiles(leastMaintainableFiles(report)) {
+		fmt.Fprintf(&buf, "| `%s` | %d | %.1f |\n", f.Path, f.Maintainability.Funcs, f.Maintainability.Index())
+	}
+
+	// Largest files
+	buf.WriteString("\n#### Largest Files\n\n")
+	buf.WriteString("| File | Lines of Code | Total Lines |\n")
+	buf.WriteString("| :--- | ---: | ---: |\n")
+	largest := report.sortedFiles(func(a, b FileResults) bool {
+		return a.LinesOfCode > b.LinesOfCode
+	})
+	for _, f := range topFiles(largest) {
+		fmt.Fprintf(&buf, "| `%s` | %d | %d |\n", f.Path, f.LinesOfCode, f.TotalLinesProcessed)
+	}
+
+	// Deepest nesting
+	buf.WriteString("\n#### Deepest Nesting\n\n")
+	buf.WriteString("| File | Max Depth | Line |\n")
+	buf.WriteString("| :--- | ---: | ---: |\n")
+	deepest := report.sortedFiles(func(a, b FileResults) bool {
+		return a.MaxCurlyBracesDepth > b.MaxCurlyBracesDepth
+	})
+	for _, f := range topFiles(deepest) {
+		fmt.Fprintf(&buf, "| `%s` | %d | %d |\n", f.Path, f.MaxCurlyBracesDepth, f.MaxCurlyBracesDepthLocation.Line)
+	}
+
+	// Worst err-check ratio
+	buf.WriteString("\n#### Highest Err-Check Ratio\n\n")
+	buf.WriteString("| File | Lines of Err-Check | Ratio |\n")
+	buf.WriteString("| :--- | ---: | ---: |\n")
+	worstErrCheck := report.sortedFiles(func(a, b FileResults) bool {
+		return errCheckRatio(a.Results) > errCheckRatio(b.Results)
+	})
+	for _, f := range topFiles(worstErrCheck) {
+		fmt.Fprintf(&buf, "| `%s` | %d | %.1f%% |\n", f.Path, f.LinesOfErrCheck, errCheckRatio(f.Results)*100)
+	}
+
+	// Most boilerplate-heavy functions
+	funcs := mostBoilerplate(report.funcs())
+	if len(funcs) > 0 {
+		buf.WriteString("\n#### Highest Err-Check Ratio Functions\n\n")
+		buf.WriteString("| Function | Location | Lines of Err-Check | Ratio |\n")
+		buf.WriteString("| :--- | :--- | ---: | ---: |\n")
+		if len(funcs) > numTopOffenders {
+			funcs = funcs[:numTopOffenders]
+		}
+		for _, f := range funcs {
+			fmt.Fprintf(&buf, "| `%s` | `%s:%d` | %d | %.1f%% |\n", f.Name, f.Location.File, f.Location.Line, f.LinesOfErrCheck, f.errCheckRatio()*100)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// summaryMetric is a single named number from Results
+type summaryMetric struct {
+	name  string
+	value func(r Results) int
+}
+
+var summaryMetrics = []summaryMetric{
+	{"Files", func(r Results) int { return r.NumOfFiles }},
+	{"Unformatted Files", func(r Results) int { return r.NumOfUnformattedFiles }},
+	{"Total Lines", func(r Results) int { return r.TotalLinesProcessed }},
+	{"Lines of Code", func(r Results) int { return r.LinesOfCode }},
+	{"Lines of Err-Check", func(r Results) int { return r.LinesOfErrCheck }},
+	{"Lines of Comments", func(r Results) int { return r.LinesOfComments }},
+	{"Lines of Whitespace", func(r Results) int { return r.LinesWhitespace }},
+	{"Inline Comments", func(r Results) int { return r.NumInlineComments }},
+	{"Logical Statements", func(r Results) int { return r.LogicalStatements }},
+	{"Lines Duplicated", func(r Results) int { return r.LinesDuplicated }},
+	{"Max Curly Braces Depth", func(r Results) int { return r.MaxCurlyBracesDepth }},
+}
+
+func topFiles(files []FileResults) []FileResults {
+	if len(files) > numTopOffenders {
+		return files[:numTopOffenders]
+	}
+	return files
+}
+
+func formatDelta(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	if n < 0 {
+		return fmt.Sprintf("%d", n)
+	}
+	return "0"
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	var sampleReport = Report{
+		Total: Results{
+			NumOfFiles:          2,
+			LinesOfCode:         30,
+			LinesOfErrCheck:     10,
+			TotalLinesProcessed: 40,
+			LogicalStatements:   12,
+			MaxCurlyBracesDepth: 3,
+		},
+		Files: []FileResults{
+			{
+				Path: "b.go",
+				Results: Results{
+					NumOfFiles:          1,
+					LinesOfCode:         10,
+					LinesOfErrCheck:     10,
+					TotalLinesProcessed: 20,
+					MaxCurlyBracesDepth: 3,
+					MaxCurlyBracesDepthLocation: Location{
+						File: "b.go",
+						Line: 7,
+					},
+				},
+				Maintainability: Maintainability{
+					Funcs:         2,
+					Complexity:    5,
+					Volume:        300.4,
+					Effort:        1500,
+					LinesOfCode:   10,
+					WeightedIndex: 650,
+				},
+			},
+			{
+				Path: "a.go",
+				Results: Results{
+					NumOfFiles:          1,
+					LinesOfCode:         20,
+					TotalLinesProcessed: 20,
+					MaxCurlyBracesDepth: 1,
+				},
+			},
+		},
+	}
+
+	var sampleBase = Report{
+		Total: Results{
+			NumOfFiles:          1,
+			LinesOfCode:         35,
+			TotalLinesProcessed: 35,
+			LogicalStatements:   10,
+			MaxCurlyBracesDepth: 3,
+		},
+	}
+
+	tests := []struct {
+		name string
+		base *Report
+		want string
+	}{
+		{
+			name: "without base",
+			want: "### Gloc Summary\n\n" +
+				"| Metric | Value |\n" +
+				"| :--- | ---: |\n" +
+				"| Files | 2 |\n" +
+				"| Unformatted Files | 0 |\n" +
+				"| Total Lines | 40 |\n" +
+				"| Lines of Code | 30 |\n" +
+				"| Lines of Err-Check | 10 |\n" +
+				"| Lines of Comments | 0 |\n" +
+				"| Lines of Whitespace | 0 |\n" +
+				"| Inline Comments | 0 |\n" +
+				"| Logical Statements | 12 |\n" +
+				"| Lines Duplicated | 0 |\n" +
+				"| Max Curly Braces Depth | 3 |\n" +
+				"\n#### Maintainability\n\n" +
+				"| Metric | Value |\n" +
+				"| :--- | ---: |\n" +
+				"| Maintainability Index | 65.0 |\n" +
+				"| Cyclomatic Complexity | 5 |\n" +
+				"| Halstead Volume | 300 |\n" +
+				"| Halstead Effort | 1500 |\n" +
+				"\n#### Least Maintainable Files\n\n" +
+				"| File | Funcs | Maintainability Index |\n" +
+				"| :--- | ---: | ---: |\n" +
+				"| `b.go` | 2 | 65.0 |\n" +
+				"\n#### Largest Files\n\n" +
+				"| File | Lines of Code | Total Lines |\n" +
+				"| :--- | ---: | ---: |\n" +
+				"| `a.go` | 20 | 20 |\n" +
+				"| `b.go` | 10 | 20 |\n" +
+				"\n#### Deepest Nesting\n\n" +
+				"| File | Max Depth | Line |\n" +
+				"| :--- | ---: | ---: |\n" +
+				"| `b.go` | 3 | 7 |\n" +
+				"| `a.go` | 1 | 0 |\n" +
+				"\n#### Highest Err-Check Ratio\n\n" +
+				"| File | Lines of Err-Check | Ratio |\n" +
+				"| :--- | ---: | ---: |\n" +
+				"| `b.go` | 10 | 50.0% |\n" +
+				"| `a.go` | 0 | 0.0% |\n",
+		},
+		{
+			name: "with base",
+			base: &sampleBase,
+			want: "### Gloc Summary\n\n" +
+				"| Metric | Value | Change |\n" +
+				"| :--- | ---: | ---: |\n" +
+				"| Files | 2 | +1 |\n" +
+				"| Unformatted Files | 0 | 0 |\n" +
+				"| Total Lines | 40 | +5 |\n" +
+				"| Lines of Code | 30 | -5 |\n" +
+				"| Lines of Err-Check | 10 | +10 |\n" +
+				"| Lines of Comments | 0 | 0 |\n" +
+				"| Lines of Whitespace | 0 | 0 |\n" +
+				"| Inline Comments | 0 | 0 |\n" +
+				"| Logical Statements | 12 | +2 |\n" +
+				"| Lines Duplicated | 0 | 0 |\n" +
+				"| Max Curly Braces Depth | 3 | 0 |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeMarkdown(&buf, sampleReport, tt.base)
+			assert.Equal(t, nil, err)
+			got := buf.String()
+			if tt.base != nil {
+				// only check the totals table
+				got = got[:len(tt.want)]
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// goModule identifies the Go module that a directory belongs to
+type goModule struct {
+	path string // module path, as declared in go.mod
+	dir  string // absolute path of the directory containing go.mod
+}
+
+// readModule reads the go.mod file in dirPath. It returns false if the directory has no go.mod file.
+func readModule(dirPath string) (goModule, bool, error) {
+	var m goModule
+
+	file, err := os.Open(joinPath(dirPath, "go.mod"))
+	if os.IsNotExist(err) {
+		return m, false, nil
+	}
+	if err != nil {
+		return m, false, err
+	}
+	defer file.Close()
+
+	m.dir, err = filepath.Abs(dirPath)
+	if err != nil {
+		return m, false, err
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module") {
+			m.path = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`)
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return m, false, err
+	}
+
+	return m, true, nil
+}
+
+// findModule looks for the module that dirPath belongs to, starting at dirPath and going up through its parents. If
+// there is none, an empty goModule is returned.
+func findModule(dirPath string) (goModule, error) {
+	dir, err := filepath.Abs(dirPath)
+	if err != nil {
+		return goModule{}, err
+	}
+
+	for {
+		m, ok, err := readModule(dir)
+		if err != nil {
+			return m, err
+		}
+		if ok {
+			return m, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return goModule{}, nil
+		}
+		dir = parent
+	}
+}
+
+// importPath returns the import path of the package in dirPath. If the directory is not part of the module, the
+// directory path itself is returned.
+func (m goModule) importPath(dirPath string) string {
+	if m.path == "" {
+		return filepath.ToSlash(dirPath)
+	}
+
+	absDir, err := filepath.Abs(dirPath)
+	if err != nil {
+		return filepath.ToSlash(dirPath)
+	}
+	rel, err := filepath.Rel(m.dir, absDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return filepath.ToSlash(dirPath)
+	}
+	if rel == "." {
+		return m.path
+	}
+	return path.Join(m.path, filepath.ToSlash(rel))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindModule(t *testing.T) {
+	root := t.TempDir()
+
+	err := os.MkdirAll(joinPath(root, "internal", "store"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(joinPath(root, "go.mod"), []byte("// comment\nmodule example.com/app\n\ngo 1.21\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := findModule(joinPath(root, "internal", "store"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, goModule{path: "example.com/app", dir: root}, got)
+
+	tests := []struct {
+		name    string
+		dirPath string
+		want    string
+	}{
+		{
+			name:    "module root",
+			dirPath: root,
+			want:    "example.com/app",
+		},
+		{
+			name:    "sub package",
+			dirPath: joinPath(root, "internal", "store"),
+			want:    "example.com/app/internal/store",
+		},
+		{
+			name:    "outside the module",
+			dirPath: "/elsewhere",
+			want:    "/elsewhere",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, got.importPath(tt.dirPath))
+		})
+	}
+}
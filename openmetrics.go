@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Kinds of files, used to label the metrics
+const (
+	fileKindProd      = "prod"
+	fileKindTest      = "test"
+	fileKindGenerated = "generated"
+)
+
+func fileKind(fr FileResults) string {
+	if fr.Generated {
+		return fileKindGenerated
+	}
+	if isTestFile(fr.Path) {
+		return fileKindTest
+	}
+	return fileKindProd
+}
+
+// openMetric is a gauge exposed for each of the module, package and file kind combinations
+type openMetric struct {
+	name  string
+	help  string
+	value func(r Results) int
+}
+
+var openMetrics = []openMetric{
+	{"gloc_files", "Number of files processed.", func(r Results) int { return r.NumOfFiles }},
+	{"gloc_unformatted_files", "Number of files that are not formatted by gofmt, only counted with --gofmt.", func(r Results) int { return r.NumOfUnformattedFiles }},
+	{"gloc_lines", "Number of lines processed.", func(r Results) int { return r.TotalLinesProcessed }},
+	{"gloc_lines_of_code", "Number of lines of code, excluding error checking.", func(r Results) int { return r.LinesOfCode }},
+	{"gloc_lines_of_err_check", "Number of lines of error checking.", func(r Results) int { return r.LinesOfErrCheck }},
+	{"gloc_lines_of_comments", "Number of lines that are only comments.", func(r Results) int { return r.LinesOfComments }},
+	{"gloc_lines_whitespace", "Number of whitespace lines.", func(r Results) int { return r.LinesWhitespace }},
+	{"gloc_inline_comments", "Number of lines of code with an inline comment.", func(r Results) int { return r.NumInlineComments }},
+	{"gloc_logical_statements", "Number of statements and declarations.", func(r Results) int { return r.LogicalStatements }},
+	{"gloc_lines_duplicated", "Number of lines of code, including error checking, that are duplicated elsewhere.", func(r Results) int { return r.LinesDuplicated }},
+	{"gloc_max_curly_braces_depth", "Maximum depth of nested curly braces.", func(r Results) int { return r.MaxCurlyBracesDepth }},
+}
+
+// openMetricsLabels are the labels that the results are grouped by
+type openMetricsLabels struct {
+	module string
+	pkg    string
+	kind   string
+}
+
+func (l openMetricsLabels) String() string {
+	return fmt.Sprintf(`{module="%s",package="%s",kind="%s"}`,
+		escapeLabelValue(l.module), escapeLabelValue(l.pkg), escapeLabelValue(l.kind))
+}
+
+// writeOpenMetrics writes the results in the OpenMetrics text exposition format, as gauges labeled by module, package
+// and file kind.
+func writeOpenMetrics(w io.Writer, report Report) error {
+	var byLabels = make(map[openMetricsLabels]Results)
+	for _, f := range report.Files {
+		l := openMetricsLabels{
+			module: f.Module,
+			pkg:    f.Package,
+			kind:   fileKind(f),
+		}
+		byLabels[l] = addResults(byLabels[l], f.Results)
+	}
+
+	var labels []openMetricsLabels
+	for l := range byLabels {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].module != labels[j].module {
+			return labels[i].module < labels[j].module
+		}
+		if labels[i].pkg != labels[j].pkg {
+			return labels[i].pkg < labels[j].pkg
+		}
+		return labels[i].kind < labels[j].kind
+	})
+
+	var buf bytes.Buffer
+	for _, m := range openMetrics {
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n", m.name)
+		fmt.Fprintf(&buf, "# HELP %s %s\n", m.name, m.help)
+		for _, l := range labels {
+			fmt.Fprintf(&buf, "%s%s %d\n", m.name, l, m.value(byLabels[l]))
+		}
+	}
+	buf.WriteString("# EOF\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// escapeLabelValue escapes the characters that are not allowed as is in a label value
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
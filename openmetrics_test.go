@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	var sampleReport = Report{
+		Files: []FileResults{
+			{
+				Path:    "store/store.go",
+				Module:  "example.com/app",
+				Package: "example.com/app/store",
+				Results: Results{NumOfFiles: 1, LinesOfCode: 20, TotalLinesProcessed: 25},
+			},
+			{
+				Path:    "store/store_test.go",
+				Module:  "example.com/app",
+				Package: "example.com/app/store",
+				Results: Results{NumOfFiles: 1, LinesOfCode: 12, TotalLinesProcessed: 15},
+			},
+			{
+				Path:      "store/store.pb.go",
+				Module:    "example.com/app",
+				Package:   "example.com/app/store",
+				Generated: true,
+				Results:   Results{NumOfFiles: 1, LinesOfCode: 100, TotalLinesProcessed: 100},
+			},
+			{
+				Path:    "store/cache.go",
+				Module:  "example.com/app",
+				Package: "example.com/app/store",
+				Results: Results{NumOfFiles: 1, NumOfUnformattedFiles: 1, LinesOfCode: 5, TotalLinesProcessed: 10},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeOpenMetrics(&buf, sampleReport)
+	assert.Equal(t, nil, err)
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, []string{
+		"# TYPE gloc_files gauge",
+		"# HELP gloc_files Number of files processed.",
+		`gloc_files{module="example.com/app",package="example.com/app/store",kind="generated"} 1`,
+		`gloc_files{module="example.com/app",package="example.com/app/store",kind="prod"} 2`,
+		`gloc_files{module="example.com/app",package="example.com/app/store",kind="test"} 1`,
+		"# TYPE gloc_unformatted_files gauge",
+		"# HELP gloc_unformatted_files Number of files that are not formatted by gofmt, only counted with --gofmt.",
+		`gloc_unformatted_files{module="example.com/app",package="example.com/app/store",kind="generated"} 0`,
+		`gloc_unformatted_files{module="example.com/app",package="example.com/app/store",kind="prod"} 1`,
+		`gloc_unformatted_files{module="example.com/app",package="example.com/app/store",kind="test"} 0`,
+		"# TYPE gloc_lines gauge",
+		"# HELP gloc_lines Number of lines processed.",
+		`gloc_lines{module="example.com/app",package="example.com/app/store",kind="generated"} 100`,
+		`gloc_lines{module="example.com/app",package="example.com/app/store",kind="prod"} 35`,
+		`gloc_lines{module="example.com/app",package="example.com/app/store",kind="test"} 15`,
+	}, lines[:15])
+	assert.Equal(t, []string{"# EOF", ""}, lines[len(lines)-2:])
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "nothing to escape",
+			s:    "github.com/teejays/gloc",
+			want: "github.com/teejays/gloc",
+		},
+		{
+			name: "windows path",
+			s:    `..\sample`,
+			want: `..\\sample`,
+		},
+		{
+			name: "quotes and new lines",
+			s:    "a \"b\"\nc",
+			want: `a \"b\"\nc`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeLabelValue(tt.s))
+		})
+	}
+}
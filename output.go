@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// Supported output formats
+const (
+	formatText        = "text"
+	formatRaw         = "raw"
+	formatMarkdown    = "markdown"
+	formatSARIF       = "sarif"
+	formatOpenMetrics = "openmetrics"
+)
+
+type outputConfig struct {
+	format   string
+	color    bool
+	sections map[string]bool // optional sections of the text output
+}
+
+// writeReport writes the report to w in the configured format. If base is not nil, formats that support it show the
+// difference between the report and the base.
+func writeReport(w io.Writer, config outputConfig, report Report, base *Report) error {
+	switch config.format {
+	case formatText:
+		return writeText(w, report, config.sections, config.color)
+	case formatRaw:
+		_, err := fmt.Fprintf(w, "Results: \n%+v\n", report.Total)
+		return err
+	case formatMarkdown:
+		return writeMarkdown(w, report, base)
+	case formatSARIF:
+		return writeSARIF(w, report)
+	case formatOpenMetrics:
+		return writeOpenMetrics(w, report)
+	default:
+		return fmt.Errorf("unsupported output format '%s'", config.format)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Report is the outcome of processing a directory. It holds the aggregated Results along with the Results of each file.
+type Report struct {
+	Total      Results
+	Files      []FileResults
+	Violations []Violation // violations that come from looking at more than one file
+	Clones     []CloneGroup
+}
+
+// FileResults represents the Results of a single file
+type FileResults struct {
+	Path        string
+	Module      string // path of the module the file belongs to, empty if there is no go.mod
+	Package     string // import path of the package the file belongs to
+	PackageName string // name of the package as declared in the file, e.g. gloc_test for an external test package
+	Generated   bool
+	Results
+	Declarations    Declarations
+	DocCoverage     DocCoverage
+	Comments        CommentStats
+	Directives      []Directive
+	Imports         []Import
+	Violations      []Violation
+	ErrorHandling   ErrorHandling
+	Concurrency     Concurrency
+	DeferPanic      DeferPanic
+	Generics        Generics
+	Shapes          Shapes
+	Tests           TestQuality
+	Funcs           []FuncStats
+	Signatures      Signatures
+	Maintainability Maintainability
+	Fragments       []Fragment // code of the funcs, to find the clones once all the files have been processed
+	Warnings        []string   // problems that kept part of the analysis from running, e.g. a file that does not parse
+
+	// Needed to count the dropped errors, once all the files have been processed
+	ErrorFuncs     []string // names of the funcs declared in the file that return an error, with a dot before methods
+	CallStatements []Symbol // calls whose results are dropped, or whose last result is ignored
+
+	// Needed to count the instantiations of generics, once all the files have been processed
+	GenericNames []string // names of the generic funcs and types declared in the file
+	GenericRefs  []Symbol // calls and index expressions that may instantiate a generic
+
+	// Needed to find the untested funcs, once all the files have been processed
+	TestCalls []string // names of the funcs and methods called from a test file
+}
+
+func addReports(a, b Report) Report {
+	var r Report
+	r.Total = addResults(a.Total, b.Total)
+	r.Files = append(r.Files, a.Files...)
+	r.Files = append(r.Files, b.Files...)
+	r.Violations = append(r.Violations, a.Violations...)
+	r.Violations = append(r.Violations, b.Violations...)
+	return r
+}
+
+// sortedFiles returns a copy of the files in the report, sorted by the given less func. Ties are broken by path so that
+// the order does not depend on the order in which directories were read.
+func (r Report) sortedFiles(less func(a, b FileResults) bool) []FileResults {
+	files := make([]FileResults, len(r.Files))
+	copy(files, r.Files)
+	sort.SliceStable(files, func(i, j int) bool {
+		if less(files[i], files[j]) {
+			return true
+		}
+		if less(files[j], files[i]) {
+			return false
+		}
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// filesByPath returns a copy of the files in the report, sorted by path
+func (r Report) filesByPath() []FileResults {
+	files := make([]FileResults, len(r.Files))
+	copy(files, r.Files)
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// warnings returns the warnings of all the files in the report, sorted by path
+func (r Report) warnings() []string {
+	var all []string
+	for _, f := range r.filesByPath() {
+		all = append(all, f.Warnings...)
+	}
+	return all
+}
+
+// errCheckRatio is the share of non-comment, non-whitespace lines that are spent on error checking
+func errCheckRatio(r Results) float64 {
+	total := r.LinesOfCode + r.LinesOfErrCheck
+	if total == 0 {
+		return 0
+	}
+	return float64(r.LinesOfErrCheck) / float64(total)
+}
+
+// PackageResults represents the combined Results of all the files in a single package directory
+type PackageResults struct {
+	Path       string
+	ImportPath string
+	Results
+	Declarations    Declarations
+	DocCoverage     DocCoverage
+	Comments        CommentStats
+	Imports         []Import
+	ErrorHandling   ErrorHandling
+	Concurrency     Concurrency
+	DeferPanic      DeferPanic
+	Generics        Generics
+	Shapes          Shapes
+	Signatures      Signatures
+	Tests           TestQuality
+	Maintainability Maintainability
+}
+
+// packages groups the files in the report by their directory, sorted by path
+func (r Report) packages() []PackageResults {
+	// The calls that drop an error can only be counted once we know all the funcs that return an error
+	errorFuncs := knownErrorFuncs(r.Files)
+
+	// Same goes for the instantiations of generics
+	var genericNames = make(map[string]bool)
+	for _, f := range r.Files {
+		for _, name := range f.GenericNames {
+			genericNames[name] = true
+		}
+	}
+
+	var byPath = make(map[string]PackageResults)
+	for _, f := range r.Files {
+		dir := filepath.Dir(f.Path)
+		p := byPath[dir]
+		p.Path = dir
+		p.ImportPath = f.Package
+		p.Results = addResults(p.Results, f.Results)
+		p.Declarations = addDeclarations(p.Declarations, f.Declarations)
+		p.DocCoverage = addDocCoverage(p.DocCoverage, f.DocCoverage)
+		p.Comments = addCommentStats(p.Comments, f.Comments)
+		p.Imports = append(p.Imports, f.Imports...)
+		p.ErrorHandling = addErrorHandling(p.ErrorHandling, f.ErrorHandling)
+		p.ErrorHandling.DroppedCalls += countErrorCalls(f.CallStatements, callDropped, f.Package, errorFuncs)
+		p.ErrorHandling.IgnoredCalls += countErrorCalls(f.CallStatements, callIgnored, f.Package, errorFuncs)
+		p.Concurrency = addConcurrency(p.Concurrency, f.Concurrency)
+		p.DeferPanic = addDeferPanic(p.DeferPanic, f.DeferPanic)
+		p.Generics = addGenerics(p.Generics, f.Generics)
+		p.Generics.Instantiations += countInstantiations(f.GenericRefs, genericNames)
+		p.Maintainability = addMaintainability(p.Maintainability, f.Maintainability)
+		p.Shapes = addShapes(p.Shapes, f.Shapes)
+		p.Signatures = addSignatures(p.Signatures, f.Signatures)
+		p.Tests = addTestQuality(p.Tests, f.Tests)
+		byPath[dir] = p
+	}
+
+	var pkgs []PackageResults
+	for _, p := range byPath {
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].Path < pkgs[j].Path
+	})
+	return pkgs
+}
+
+// percentOf returns n as a percentage of total
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// directives returns all the directives found in the report, sorted by file and line
+func (r Report) directives() []Directive {
+	var all []Directive
+	for _, f := range r.Files {
+		all = append(all, f.Directives...)
+	}
+	sortDirectives(all)
+	return all
+}
+
+// namedCount is the number of times something with a name was seen
+type namedCount struct {
+	name  string
+	count int
+}
+
+// sortedCounts returns the counts in the map, the largest first. Ties are sorted by name.
+func sortedCounts(counts map[string]int) []namedCount {
+	var sorted []namedCount
+	for name, count := range counts {
+		sorted = append(sorted, namedCount{name: name, count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
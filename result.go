@@ -1,16 +1,24 @@
 package main
 
+import (
+	"sort"
+)
+
 // Results is the final and intermediary response after processing a unit of code
 type Results struct {
 	NumOfFiles                  int
+	NumOfUnformattedFiles       int // files that are not formatted by gofmt, only counted with --gofmt
 	LinesOfCode                 int
 	LinesOfErrCheck             int
 	LinesOfComments             int
 	LinesWhitespace             int
 	TotalLinesProcessed         int
 	NumInlineComments           int
+	LogicalStatements           int // statements and declarations, regardless of the formatting
+	LinesDuplicated             int // lines of code that are in a clone, only known once all the files have been processed
 	MaxCurlyBracesDepth         int
 	MaxCurlyBracesDepthLocation Location
+	DeepNesting                 []NestingLocation
 }
 
 // Location represents the location of a certain event in code
@@ -19,9 +27,28 @@ type Location struct {
 	Line int
 }
 
+// NestingLocation represents a place in code where curly braces are nested deeper than the configured depth
+type NestingLocation struct {
+	Location
+	Depth    int    // the maximum depth reached before going back up
+	Function string // name of the enclosing function, if any
+}
+
+// locationLess orders locations by file and then by line. Empty locations come last.
+func locationLess(a, b Location) bool {
+	if a.File == "" || b.File == "" {
+		return a.File != ""
+	}
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	return a.Line < b.Line
+}
+
 func addResults(a, b Results) Results {
 	var r Results
 	r.NumOfFiles = a.NumOfFiles + b.NumOfFiles
+	r.NumOfUnformattedFiles = a.NumOfUnformattedFiles + b.NumOfUnformattedFiles
 	r.LinesOfCode = a.LinesOfCode + b.LinesOfCode
 	r.LinesOfComments = a.LinesOfComments + b.LinesOfComments
 	r.LinesOfErrCheck = a.LinesOfErrCheck + b.LinesOfErrCheck
@@ -29,12 +56,24 @@ func addResults(a, b Results) Results {
 	r.TotalLinesProcessed = a.TotalLinesProcessed + b.TotalLinesProcessed
 
 	r.NumInlineComments = a.NumInlineComments + b.NumInlineComments
+	r.LogicalStatements = a.LogicalStatements + b.LogicalStatements
+	r.LinesDuplicated = a.LinesDuplicated + b.LinesDuplicated
 
 	r.MaxCurlyBracesDepth = maxInt(a.MaxCurlyBracesDepth, b.MaxCurlyBracesDepth)
 	r.MaxCurlyBracesDepthLocation = a.MaxCurlyBracesDepthLocation
-	if r.MaxCurlyBracesDepth == b.MaxCurlyBracesDepth {
+	if b.MaxCurlyBracesDepth > a.MaxCurlyBracesDepth {
 		r.MaxCurlyBracesDepthLocation = b.MaxCurlyBracesDepthLocation
 	}
+	// On a tie, pick the earliest location so that the result doesn't depend on the order files were read in
+	if b.MaxCurlyBracesDepth == a.MaxCurlyBracesDepth && locationLess(b.MaxCurlyBracesDepthLocation, a.MaxCurlyBracesDepthLocation) {
+		r.MaxCurlyBracesDepthLocation = b.MaxCurlyBracesDepthLocation
+	}
+
+	r.DeepNesting = append(r.DeepNesting, a.DeepNesting...)
+	r.DeepNesting = append(r.DeepNesting, b.DeepNesting...)
+	sort.SliceStable(r.DeepNesting, func(i, j int) bool {
+		return locationLess(r.DeepNesting[i].Location, r.DeepNesting[j].Location)
+	})
 
 	return r
 }
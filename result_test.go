@@ -70,6 +70,33 @@ func TestAddResults(t *testing.T) {
 			b:    sampleResultsB,
 			want: sampleResultsAB,
 		},
+		{
+			name: "same max depth keeps the earliest location",
+			a: Results{
+				MaxCurlyBracesDepth:         3,
+				MaxCurlyBracesDepthLocation: Location{File: "b.go", Line: 4},
+				DeepNesting: []NestingLocation{
+					{Location: Location{File: "b.go", Line: 4}, Depth: 3},
+				},
+			},
+			b: Results{
+				MaxCurlyBracesDepth:         3,
+				MaxCurlyBracesDepthLocation: Location{File: "a.go", Line: 9},
+				DeepNesting: []NestingLocation{
+					{Location: Location{File: "a.go", Line: 9}, Depth: 3},
+					{Location: Location{File: "a.go", Line: 2}, Depth: 3},
+				},
+			},
+			want: Results{
+				MaxCurlyBracesDepth:         3,
+				MaxCurlyBracesDepthLocation: Location{File: "a.go", Line: 9},
+				DeepNesting: []NestingLocation{
+					{Location: Location{File: "a.go", Line: 2}, Depth: 3},
+					{Location: Location{File: "a.go", Line: 9}, Depth: 3},
+					{Location: Location{File: "b.go", Line: 4}, Depth: 3},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
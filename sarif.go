@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifRules describes each of the rules a Violation can be reported for
+var sarifRules = []sarifRule{
+	{
+		ID:               ruleFuncLength,
+		ShortDescription: sarifMessage{Text: "Function is longer than the configured maximum number of lines"},
+	},
+	{
+		ID:               ruleNestingDepth,
+		ShortDescription: sarifMessage{Text: "Curly braces are nested deeper than the configured maximum depth"},
+	},
+	{
+		ID:               ruleFileLength,
+		ShortDescription: sarifMessage{Text: "File is longer than the configured maximum number of lines"},
+	},
+	{
+		ID:               ruleInterfaceSize,
+		ShortDescription: sarifMessage{Text: "Interface has more than the configured maximum number of methods"},
+	},
+	{
+		ID:               ruleParamCount,
+		ShortDescription: sarifMessage{Text: "Function has more than the configured maximum number of params"},
+	},
+	{
+		ID:               ruleForbiddenImport,
+		ShortDescription: sarifMessage{Text: "Package imports a package that the configured layering rules forbid"},
+	},
+	{
+		ID:               ruleImportCycle,
+		ShortDescription: sarifMessage{Text: "Import is part of an import cycle between packages"},
+	},
+	{
+		ID:               ruleDeferInLoop,
+		ShortDescription: sarifMessage{Text: "Defer inside a loop only runs when the function returns"},
+	},
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// writeSARIF writes the Violations found in the report as a SARIF 2.1.0 log
+func writeSARIF(w io.Writer, report Report) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "gloc",
+				InformationURI: "https://github.com/teejays/gloc",
+				Rules:          sarifRules,
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	for _, v := range report.violations() {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  v.Rule,
+			Level:   "warning",
+			Message: sarifMessage{Text: v.Message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: sarifURI(v.File)},
+						Region: sarifRegion{
+							StartLine:   v.Region.StartLine,
+							StartColumn: v.Region.StartColumn,
+							EndLine:     v.Region.EndLine,
+							EndColumn:   v.Region.EndColumn,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifURI converts a file path into a URI. Relative paths stay relative (to the directory gloc was run from), so that
+// code scanning tools can resolve them against the repository root.
+func sarifURI(path string) string {
+	if filepath.IsAbs(path) {
+		return "file://" + filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
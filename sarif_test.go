@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	var sampleReport = Report{
+		Files: []FileResults{
+			{
+				Path: "./pkg/b.go",
+				Violations: []Violation{
+					{
+						Rule:    ruleFileLength,
+						Message: "file has 120 lines (max 100)",
+						File:    "./pkg/b.go",
+						Region:  Region{StartLine: 1, EndLine: 120},
+					},
+				},
+			},
+			{
+				Path: "./a.go",
+				Violations: []Violation{
+					{
+						Rule:    ruleFuncLength,
+						Message: "function main has 60 lines (max 50)",
+						File:    "./a.go",
+						Region:  Region{StartLine: 3, StartColumn: 1, EndLine: 62, EndColumn: 2},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeSARIF(&buf, sampleReport)
+	assert.Equal(t, nil, err)
+
+	var got sarifLog
+	err = json.Unmarshal(buf.Bytes(), &got)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "2.1.0", got.Version)
+	assert.Equal(t, 1, len(got.Runs))
+	assert.Equal(t, []sarifResult{
+		{
+			RuleID:  ruleFuncLength,
+			Level:   "warning",
+			Message: sarifMessage{Text: "function main has 60 lines (max 50)"},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "a.go"},
+						Region:           sarifRegion{StartLine: 3, StartColumn: 1, EndLine: 62, EndColumn: 2},
+					},
+				},
+			},
+		},
+		{
+			RuleID:  ruleFileLength,
+			Level:   "warning",
+			Message: sarifMessage{Text: "file has 120 lines (max 100)"},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "pkg/b.go"},
+						Region:           sarifRegion{StartLine: 1, EndLine: 120},
+					},
+				},
+			},
+		},
+	}, got.Runs[0].Results)
+}
+
+func TestSarifURI(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "relative path",
+			path: "./pkg/main.go",
+			want: "pkg/main.go",
+		},
+		{
+			name: "parent path",
+			path: "../sample/main.go",
+			want: "../sample/main.go",
+		},
+		{
+			name: "absolute path",
+			path: "/go/src/sample/main.go",
+			want: "file:///go/src/sample/main.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sarifURI(tt.path))
+		})
+	}
+}
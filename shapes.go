@@ -0,0 +1,149 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Kinds of TypeShape
+const (
+	shapeStruct    = "struct"
+	shapeInterface = "interface"
+)
+
+// Shapes sums up the shape of the structs and interfaces declared in a unit of code
+type Shapes struct {
+	Structs            int
+	Fields             int
+	EmbeddedFields     int
+	TaggedFields       int
+	Tags               map[string]int // number of fields with a tag for the key, e.g. json, yaml or db
+	Interfaces         int
+	Methods            int
+	EmbeddedInterfaces int
+	Types              []TypeShape
+}
+
+// TypeShape is the shape of a single struct or interface
+type TypeShape struct {
+	Name     string
+	Kind     string
+	Location Location
+	Members  int // fields of a struct, or methods of an interface
+	Embedded int
+}
+
+// tagKeyRegexp matches the keys of a struct tag, e.g. json and db in `json:"id" db:"user_id"`
+var tagKeyRegexp = regexp.MustCompile(`([^\s:"]+):"(?:[^"\\]|\\.)*"`)
+
+// analyzeShapes finds the structs and interfaces declared in the file, including the ones declared inside funcs
+func analyzeShapes(fset *token.FileSet, file *ast.File, filePath string) Shapes {
+	var s Shapes
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		shape := TypeShape{
+			Name:     spec.Name.Name,
+			Location: Location{File: filePath, Line: fset.Position(spec.Pos()).Line},
+		}
+
+		switch t := spec.Type.(type) {
+		case *ast.StructType:
+			shape.Kind = shapeStruct
+			s.Structs++
+			for _, field := range t.Fields.List {
+				if len(field.Names) == 0 {
+					shape.Embedded++
+				}
+				shape.Members += maxInt(len(field.Names), 1)
+				if field.Tag == nil {
+					continue
+				}
+				tag, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+				keys := tagKeyRegexp.FindAllStringSubmatch(tag, -1)
+				if len(keys) == 0 {
+					continue
+				}
+				s.TaggedFields += maxInt(len(field.Names), 1)
+				for _, key := range keys {
+					if s.Tags == nil {
+						s.Tags = make(map[string]int)
+					}
+					s.Tags[key[1]] += maxInt(len(field.Names), 1)
+				}
+			}
+			s.Fields += shape.Members
+			s.EmbeddedFields += shape.Embedded
+
+		case *ast.InterfaceType:
+			shape.Kind = shapeInterface
+			s.Interfaces++
+			for _, field := range t.Methods.List {
+				if len(field.Names) == 0 {
+					shape.Embedded++
+					continue
+				}
+				shape.Members += len(field.Names)
+			}
+			s.Methods += shape.Members
+			s.EmbeddedInterfaces += shape.Embedded
+
+		default:
+			return true
+		}
+
+		s.Types = append(s.Types, shape)
+		return true
+	})
+	return s
+}
+
+// largestTypes returns the structs or interfaces, the ones with the most members first
+func largestTypes(types []TypeShape, kind string) []TypeShape {
+	var largest []TypeShape
+	for _, t := range types {
+		if t.Kind == kind {
+			largest = append(largest, t)
+		}
+	}
+	sort.SliceStable(largest, func(i, j int) bool {
+		a, b := largest[i], largest[j]
+		if a.Members != b.Members {
+			return a.Members > b.Members
+		}
+		return locationLess(a.Location, b.Location)
+	})
+	return largest
+}
+
+func addShapes(a, b Shapes) Shapes {
+	var s Shapes
+	s.Structs = a.Structs + b.Structs
+	s.Fields = a.Fields + b.Fields
+	s.EmbeddedFields = a.EmbeddedFields + b.EmbeddedFields
+	s.TaggedFields = a.TaggedFields + b.TaggedFields
+	s.Interfaces = a.Interfaces + b.Interfaces
+	s.Methods = a.Methods + b.Methods
+	s.EmbeddedInterfaces = a.EmbeddedInterfaces + b.EmbeddedInterfaces
+
+	for _, tags := range []map[string]int{a.Tags, b.Tags} {
+		for key, n := range tags {
+			if s.Tags == nil {
+				s.Tags = make(map[string]int)
+			}
+			s.Tags[key] += n
+		}
+	}
+
+	s.Types = append(s.Types, a.Types...)
+	s.Types = append(s.Types, b.Types...)
+	return s
+}
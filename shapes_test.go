@@ -0,0 +1,71 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeShapes(t *testing.T) {
+	src := `package sample
+
+type User struct {
+	Base
+	*Audit
+	ID         int    ` + "`json:\"id\" db:\"user_id\"`" + `
+	First, Last string ` + "`json:\"name\" yaml:\"name\"`" + `
+	password   string
+	Notes      string ` + "`validate`" + `
+}
+
+type Store interface {
+	io.Closer
+	Get(id int) (User, error)
+	Put(u User) error
+}
+
+type ID int
+
+func f() {
+	type local struct{ a, b int }
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, Shapes{
+		Structs:            2,
+		Fields:             9,
+		EmbeddedFields:     2,
+		TaggedFields:       3,
+		Tags:               map[string]int{"json": 3, "db": 1, "yaml": 2},
+		Interfaces:         1,
+		Methods:            2,
+		EmbeddedInterfaces: 1,
+		Types: []TypeShape{
+			{Name: "User", Kind: shapeStruct, Location: Location{File: "sample.go", Line: 3}, Members: 7, Embedded: 2},
+			{Name: "Store", Kind: shapeInterface, Location: Location{File: "sample.go", Line: 12}, Members: 2, Embedded: 1},
+			{Name: "local", Kind: shapeStruct, Location: Location{File: "sample.go", Line: 21}, Members: 2},
+		},
+	}, analyzeShapes(fset, file, "sample.go"))
+}
+
+func TestLargestTypes(t *testing.T) {
+	types := []TypeShape{
+		{Name: "a", Kind: shapeStruct, Location: Location{File: "a.go", Line: 1}, Members: 2},
+		{Name: "b", Kind: shapeInterface, Location: Location{File: "a.go", Line: 5}, Members: 9},
+		{Name: "c", Kind: shapeStruct, Location: Location{File: "a.go", Line: 9}, Members: 4},
+		{Name: "d", Kind: shapeStruct, Location: Location{File: "a.go", Line: 3}, Members: 4},
+	}
+
+	var names []string
+	for _, s := range largestTypes(types, shapeStruct) {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"d", "c", "a"}, names)
+}
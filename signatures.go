@@ -0,0 +1,125 @@
+package main
+
+import (
+	"go/ast"
+	"sort"
+)
+
+// Positions of the error result of a func
+const (
+	errorResultLast      = "last"
+	errorResultElsewhere = "elsewhere"
+)
+
+// Signature describes the params and results of a single func. The receiver of a method is not a param.
+type Signature struct {
+	Params       int
+	Results      int
+	NamedResults bool
+	Variadic     bool
+	ErrorResult  string // errorResultLast, errorResultElsewhere, or empty if the func does not return an error
+}
+
+// Signatures sums up the signatures of the funcs in a unit of code
+type Signatures struct {
+	Funcs          int
+	Params         int
+	Results        int
+	NamedResults   int // funcs with named results
+	Variadic       int
+	ErrorLast      int // funcs that return an error as their last result
+	ErrorElsewhere int // funcs that return an error, but not as their last result
+}
+
+// analyzeSignature counts the params and results of the func type
+func analyzeSignature(fn *ast.FuncType) Signature {
+	var s Signature
+	for _, field := range fn.Params.List {
+		s.Params += maxInt(len(field.Names), 1)
+		if _, ok := field.Type.(*ast.Ellipsis); ok {
+			s.Variadic = true
+		}
+	}
+	if fn.Results == nil {
+		return s
+	}
+	last := len(fn.Results.List) - 1
+	for i, field := range fn.Results.List {
+		n := maxInt(len(field.Names), 1)
+		s.Results += n
+		if len(field.Names) > 0 {
+			s.NamedResults = true
+		}
+		if ident, ok := field.Type.(*ast.Ident); !ok || ident.Name != "error" {
+			continue
+		}
+		// A func that returns more than one error has one that is not last
+		if i == last && n == 1 && s.ErrorResult == "" {
+			s.ErrorResult = errorResultLast
+		} else {
+			s.ErrorResult = errorResultElsewhere
+		}
+	}
+	return s
+}
+
+// signaturesOf sums up the signatures of the funcs
+func signaturesOf(funcs []FuncStats) Signatures {
+	var s Signatures
+	for _, f := range funcs {
+		s.Funcs++
+		s.Params += f.Signature.Params
+		s.Results += f.Signature.Results
+		if f.Signature.NamedResults {
+			s.NamedResults++
+		}
+		if f.Signature.Variadic {
+			s.Variadic++
+		}
+		switch f.Signature.ErrorResult {
+		case errorResultLast:
+			s.ErrorLast++
+		case errorResultElsewhere:
+			s.ErrorElsewhere++
+		}
+	}
+	return s
+}
+
+// longestParamLists returns the functions that take params, the ones with the most params first
+func longestParamLists(funcs []FuncStats) []FuncStats {
+	var ranked []FuncStats
+	for _, f := range funcs {
+		if f.Signature.Params > 0 {
+			ranked = append(ranked, f)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Signature.Params != b.Signature.Params {
+			return a.Signature.Params > b.Signature.Params
+		}
+		return locationLess(a.Location, b.Location)
+	})
+	return ranked
+}
+
+// avgParams is the average number of params per func
+func (s Signatures) avgParams() float64 {
+	if s.Funcs == 0 {
+		return 0
+	}
+	return float64(s.Params) / float64(s.Funcs)
+}
+
+func addSignatures(a, b Signatures) Signatures {
+	var s Signatures
+	s.Funcs = a.Funcs + b.Funcs
+	s.Params = a.Params + b.Params
+	s.Results = a.Results + b.Results
+	s.NamedResults = a.NamedResults + b.NamedResults
+	s.Variadic = a.Variadic + b.Variadic
+	s.ErrorLast = a.ErrorLast + b.ErrorLast
+	s.ErrorElsewhere = a.ErrorElsewhere + b.ErrorElsewhere
+	return s
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeSignature(t *testing.T) {
+	tests := []struct {
+		name string
+		decl string
+		want Signature
+	}{
+		{
+			name: "no params or results",
+			decl: "func f() {}",
+			want: Signature{},
+		},
+		{
+			name: "grouped params",
+			decl: "func f(a, b int, c string) bool { return true }",
+			want: Signature{Params: 3, Results: 1},
+		},
+		{
+			name: "receiver is not a param",
+			decl: "func (r *Reader) Read(p []byte) (int, error) { return 0, nil }",
+			want: Signature{Params: 1, Results: 2, ErrorResult: errorResultLast},
+		},
+		{
+			name: "unnamed params",
+			decl: "func f(int, string) error { return nil }",
+			want: Signature{Params: 2, Results: 1, ErrorResult: errorResultLast},
+		},
+		{
+			name: "variadic",
+			decl: "func f(format string, args ...interface{}) {}",
+			want: Signature{Params: 2, Variadic: true},
+		},
+		{
+			name: "named results",
+			decl: "func f() (n int, err error) { return }",
+			want: Signature{Results: 2, NamedResults: true, ErrorResult: errorResultLast},
+		},
+		{
+			name: "error first",
+			decl: "func f() (error, int) { return nil, 0 }",
+			want: Signature{Results: 2, ErrorResult: errorResultElsewhere},
+		},
+		{
+			name: "two errors",
+			decl: "func f() (a, b error) { return }",
+			want: Signature{Results: 2, NamedResults: true, ErrorResult: errorResultElsewhere},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "sample.go", "package sample\n\n"+tt.decl+"\n", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fn := file.Decls[0].(*ast.FuncDecl)
+			assert.Equal(t, tt.want, analyzeSignature(fn.Type))
+		})
+	}
+}
+
+func TestLongestParamLists(t *testing.T) {
+	funcs := []FuncStats{
+		{Name: "none", Location: Location{File: "a.go", Line: 1}},
+		{Name: "two", Location: Location{File: "a.go", Line: 9}, Signature: Signature{Params: 2}},
+		{Name: "five", Location: Location{File: "a.go", Line: 5}, Signature: Signature{Params: 5}},
+		{Name: "other", Location: Location{File: "a.go", Line: 3}, Signature: Signature{Params: 2}},
+	}
+
+	var names []string
+	for _, f := range longestParamLists(funcs) {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"five", "other", "two"}, names)
+	assert.Equal(t, Signatures{Funcs: 4, Params: 9}, signaturesOf(funcs))
+}
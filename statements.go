@@ -0,0 +1,25 @@
+package main
+
+import (
+	"go/ast"
+)
+
+// countStatements counts the logical statements of the file, as defined by the Go grammar: the statements in the
+// bodies of the funcs, and the func, type, const and var declarations. Blocks, case clauses and labels only structure
+// other statements, and are not counted. Neither are the package clause and the imports. Unlike the line counts, this
+// does not depend on how the code is formatted.
+func countStatements(file *ast.File) int {
+	var n int
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FuncDecl, *ast.ValueSpec, *ast.TypeSpec:
+			n++
+		case *ast.BlockStmt, *ast.EmptyStmt, *ast.LabeledStmt, *ast.CaseClause, *ast.CommClause, *ast.DeclStmt:
+			// The specs of a declaration are counted on their own
+		case ast.Stmt:
+			n++
+		}
+		return true
+	})
+	return n
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{
+			name: "package and imports only",
+			src:  "package sample\n\nimport \"fmt\"\n",
+			want: 0,
+		},
+		{
+			// type, const x2, func, and the return
+			name: "declarations",
+			src: `package sample
+
+type ID int
+
+const (
+	a = 1
+	b = 2
+)
+
+func f() int { return a }
+`,
+			want: 5,
+		},
+		{
+			// func, var, if, assign, switch, two expression statements, labeled for, break, return
+			name: "statements",
+			src: `package sample
+
+func f(x int) int {
+	var y int
+	if x > 0 {
+		y = x
+	}
+	switch y {
+	case 1:
+		g()
+	default:
+		h()
+	}
+loop:
+	for {
+		break loop
+	}
+	return y
+}
+`,
+			want: 10,
+		},
+		{
+			name: "formatting does not matter",
+			src: `package sample
+
+var config = Config{
+	Name:    "gloc",
+	Verbose: true,
+	Depth:   3,
+}
+`,
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "sample.go", tt.src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, countStatements(file))
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// assertionPackages are the import paths of the assertion libraries whose calls are counted as assertions
+var assertionPackages = map[string]bool{
+	"github.com/stretchr/testify/assert":  true,
+	"github.com/stretchr/testify/require": true,
+	"gotest.tools/assert":                 true,
+	"gotest.tools/v3/assert":              true,
+}
+
+// TestQuality sums up how the tests in a unit of code are written
+type TestQuality struct {
+	Tests       int // TestXxx funcs
+	TableDriven int // tests that range over a slice of anonymous structs and call t.Run in the loop
+	Subtests    int // t.Run calls
+	Parallel    int // t.Parallel calls
+	Assertions  int // calls to an assertion library, such as testify's assert and require
+	Failures    int // t.Error, t.Errorf, t.Fatal and t.Fatalf calls
+	Skips       int // t.Skip, t.Skipf and t.SkipNow calls
+	Mapped      int // tests that are named after a func of their package
+	Funcs       []TestFunc
+}
+
+// TestFunc is a single TestXxx func
+type TestFunc struct {
+	Name        string
+	Location    Location
+	TableDriven bool
+	Tested      string // func of the package the test is named after, e.g. "Report.packages" for TestReport_packages
+}
+
+// analyzeTests finds the tests of a _test.go file and counts how they check their results. Only the *testing.T params
+// are looked at, so helpers that take a testing.TB are not.
+func analyzeTests(fset *token.FileSet, file *ast.File, filePath string) TestQuality {
+	var q TestQuality
+	pkgNames := importNames(file)
+	tNames := testingTNames(file, pkgNames)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if importPath, _, ok := pkgFunc(call.Fun, pkgNames); ok && assertionPackages[importPath] {
+			q.Assertions++
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || !tNames[x.Name] {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "Run":
+			q.Subtests++
+		case "Parallel":
+			q.Parallel++
+		case "Error", "Errorf", "Fatal", "Fatalf":
+			q.Failures++
+		case "Skip", "Skipf", "SkipNow":
+			q.Skips++
+		}
+		return true
+	})
+
+	// Tables can be declared at the top level of the file too
+	var tables = make(map[string]bool)
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok {
+			addTables(gen, tables)
+		}
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || !isTestFunc(fn, pkgNames) {
+			continue
+		}
+		t := TestFunc{
+			Name:        fn.Name.Name,
+			Location:    Location{File: filePath, Line: fset.Position(fn.Pos()).Line},
+			TableDriven: isTableDriven(fn.Body, tables, tNames),
+		}
+		q.Tests++
+		if t.TableDriven {
+			q.TableDriven++
+		}
+		q.Funcs = append(q.Funcs, t)
+	}
+	return q
+}
+
+// isTestFunc tells whether the func is run by go test as a test, i.e. is a TestXxx func that takes a *testing.T
+func isTestFunc(fn *ast.FuncDecl, pkgNames map[string]string) bool {
+	if fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(fn.Name.Name[len("Test"):]); unicode.IsLower(r) {
+		return false
+	}
+	params := fn.Type.Params.List
+	return len(params) == 1 && maxInt(len(params[0].Names), 1) == 1 && isTestingT(params[0].Type, pkgNames)
+}
+
+// isTestingT tells whether the type is *testing.T
+func isTestingT(expr ast.Expr, pkgNames map[string]string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	importPath, name, ok := pkgFunc(star.X, pkgNames)
+	return ok && importPath == "testing" && name == "T"
+}
+
+// testingTNames returns the names of the *testing.T params of the funcs in the file, including the ones of the func
+// literals passed to t.Run
+func testingTNames(file *ast.File, pkgNames map[string]string) map[string]bool {
+	var names = make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncType)
+		if !ok {
+			return true
+		}
+		for _, field := range fn.Params.List {
+			if !isTestingT(field.Type, pkgNames) {
+				continue
+			}
+			for _, name := range field.Names {
+				names[name.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// isTable tells whether the expression is a slice or array of anonymous structs, i.e. the table of a table-driven test
+func isTable(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	arr, ok := lit.Type.(*ast.ArrayType)
+	if !ok {
+		return false
+	}
+	_, ok = arr.Elt.(*ast.StructType)
+	return ok
+}
+
+// addTables adds the names of the vars that the declaration assigns a table to
+func addTables(gen *ast.GenDecl, tables map[string]bool) {
+	for _, spec := range gen.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for i, v := range vs.Values {
+			if isTable(v) && i < len(vs.Names) {
+				tables[vs.Names[i].Name] = true
+			}
+		}
+	}
+}
+
+// isTableDriven tells whether the body of the test ranges over a table, calling t.Run in the loop. tables holds the
+// names of the vars declared with a table outside of the test.
+func isTableDriven(body *ast.BlockStmt, tables map[string]bool, tNames map[string]bool) bool {
+	var local = make(map[string]bool)
+	for name := range tables {
+		local[name] = true
+	}
+
+	var found bool
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			addTables(n, local)
+		case *ast.AssignStmt:
+			for i, rhs := range n.Rhs {
+				if !isTable(rhs) || i >= len(n.Lhs) {
+					continue
+				}
+				if ident, ok := n.Lhs[i].(*ast.Ident); ok {
+					local[ident.Name] = true
+				}
+			}
+		case *ast.RangeStmt:
+			ident, ok := n.X.(*ast.Ident)
+			if ((ok && local[ident.Name]) || isTable(n.X)) && callsRun(n.Body, tNames) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// callsRun tells whether t.Run is called in the block
+func callsRun(block *ast.BlockStmt, tNames map[string]bool) bool {
+	var found bool
+	ast.Inspect(block, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Run" {
+			if x, ok := sel.X.(*ast.Ident); ok && tNames[x.Name] {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// mapTests finds the func each test is named after, among the funcs declared in the non-test files of its package
+// directory. TestFoo maps to Foo or foo, and TestType_Method to Type.Method, or to Type if there is no such method.
+func mapTests(report Report) Report {
+	var funcs = make(map[string]bool)
+	for _, f := range report.Files {
+		if isTestFile(f.Path) {
+			continue
+		}
+		for _, fn := range f.Funcs {
+			funcs[filepath.Join(filepath.Dir(f.Path), fn.Name)] = true
+		}
+	}
+
+	for i, f := range report.Files {
+		tests := &report.Files[i].Tests
+		tests.Mapped = 0
+		for j, t := range tests.Funcs {
+			tests.Funcs[j].Tested = testedFunc(t.Name, filepath.Dir(f.Path), funcs)
+			if tests.Funcs[j].Tested != "" {
+				tests.Mapped++
+			}
+		}
+	}
+	return report
+}
+
+// testedFunc returns the func the test is named after, or an empty string if there is none in the dir. funcs holds the
+// funcs of the project, joined to the dir they are declared in.
+func testedFunc(testName, dir string, funcs map[string]bool) string {
+	name := strings.TrimPrefix(testName, "Test")
+	var candidates []string
+	if parts := strings.SplitN(name, "_", 2); len(parts) == 2 {
+		typ, method := parts[0], parts[1]
+		candidates = append(candidates, typ+"."+method, lowerFirst(typ)+"."+method, typ, lowerFirst(typ))
+	} else {
+		candidates = append(candidates, name, lowerFirst(name))
+	}
+	for _, c := range candidates {
+		if c != "" && funcs[filepath.Join(dir, c)] {
+			return c
+		}
+	}
+	return ""
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+func addTestQuality(a, b TestQuality) TestQuality {
+	var q TestQuality
+	q.Tests = a.Tests + b.Tests
+	q.TableDriven = a.TableDriven + b.TableDriven
+	q.Subtests = a.Subtests + b.Subtests
+	q.Parallel = a.Parallel + b.Parallel
+	q.Assertions = a.Assertions + b.Assertions
+	q.Failures = a.Failures + b.Failures
+	q.Skips = a.Skips + b.Skips
+	q.Mapped = a.Mapped + b.Mapped
+	q.Funcs = append(q.Funcs, a.Funcs...)
+	q.Funcs = append(q.Funcs, b.Funcs...)
+	return q
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeTests(t *testing.T) {
+	src := `package sample
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var cases = []struct{ in, want int }{{1, 2}}
+
+func TestDouble(t *testing.T) {
+	t.Parallel()
+	for _, c := range cases {
+		t.Run("", func(t *testing.T) {
+			require.Equal(t, c.want, double(c.in))
+		})
+	}
+}
+
+func TestReader_Read(t *testing.T) {
+	if testing.Short() {
+		t.Skip("slow")
+	}
+	var inputs = []struct{ name string }{{"a"}, {"b"}}
+	for _, in := range inputs {
+		if in.name == "" {
+			t.Errorf("no name")
+		}
+	}
+	t.Run("empty", func(t *testing.T) {
+		t.Fatal("not implemented")
+	})
+}
+
+func Testing(t *testing.T) {}
+
+func TestMain(m *testing.M) {}
+
+func helper(t *testing.T) {
+	t.Helper()
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sample_test.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, TestQuality{
+		Tests:       2,
+		TableDriven: 1,
+		Subtests:    2,
+		Parallel:    1,
+		Assertions:  1,
+		Failures:    2,
+		Skips:       1,
+		Funcs: []TestFunc{
+			{Name: "TestDouble", Location: Location{File: "sample_test.go", Line: 11}, TableDriven: true},
+			{Name: "TestReader_Read", Location: Location{File: "sample_test.go", Line: 20}},
+		},
+	}, analyzeTests(fset, file, "sample_test.go"))
+}
+
+func TestAnalyzeTestsOwnFixture(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "line_test.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := analyzeTests(fset, file, "line_test.go")
+	assert.Equal(t, 1, q.Tests)
+	assert.Equal(t, 1, q.TableDriven)
+	assert.Equal(t, 1, q.Subtests)
+	assert.Equal(t, 1, q.Assertions)
+}
+
+func TestMapTests(t *testing.T) {
+	report := Report{
+		Files: []FileResults{
+			{Path: "pkg/a.go", Funcs: []FuncStats{{Name: "Open"}, {Name: "Reader.Read"}, {Name: "writeText"}}},
+			{Path: "pkg/a_test.go", Funcs: []FuncStats{{Name: "TestHelper"}}, Tests: TestQuality{
+				Tests: 5,
+				Funcs: []TestFunc{{Name: "TestOpen"}, {Name: "TestReader_Read"}, {Name: "TestReader_Close"}, {Name: "TestWriteText_empty"}, {Name: "TestHelper"}},
+			}},
+			{Path: "other/b_test.go", Tests: TestQuality{Tests: 1, Funcs: []TestFunc{{Name: "TestOpen"}}}},
+		},
+	}
+
+	report = mapTests(report)
+	var tested []string
+	for _, f := range report.Files {
+		for _, t := range f.Tests.Funcs {
+			tested = append(tested, t.Tested)
+		}
+	}
+	assert.Equal(t, []string{"Open", "Reader.Read", "", "writeText", "", ""}, tested)
+	assert.Equal(t, 3, report.Files[1].Tests.Mapped)
+	assert.Equal(t, 0, report.Files[2].Tests.Mapped)
+}
@@ -0,0 +1,663 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape codes used when the output is colored
+const (
+	colorReset = "\033[0m"
+	colorBold  = "\033[1m"
+	colorDim   = "\033[2m"
+	colorCyan  = "\033[36m"
+)
+
+// Number of rows in the tables that list the top of a ranking
+const (
+	numMostImported    = 10
+	numMostBoilerplate = 10
+	numMostConcurrent  = 10
+	numLeastMaintained = 10
+	numLargestClones   = 10
+	numLargestTypes    = 10
+	numLongestParams   = 10
+)
+
+// textSection is an optional part of the text output, written after the package table when it is asked for
+type textSection struct {
+	name  string
+	write func(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool)
+}
+
+// textSections are the sections of the text output that can be left out, in the order they are written
+var textSections = []textSection{
+	{"declarations", writeDeclarations},
+	{"docs", writeDocCoverage},
+	{"comments", writeComments},
+	{"errors", writeErrorHandling},
+	{"maintainability", writeMaintainability},
+	{"params", writeSignatures},
+	{"defers", writeDeferPanic},
+	{"shapes", writeShapes},
+	{"generics", writeGenerics},
+	{"concurrency", writeConcurrency},
+	{"tests", writeTests},
+	{"imports", writeImports},
+	{"directives", writeDirectives},
+	{"clones", writeClones},
+}
+
+// sectionAll turns on all the sections of the text output, which is the default
+const sectionAll = "all"
+
+// parseSections parses a comma separated list of the names of the text sections, e.g. "docs,errors"
+func parseSections(s string) (map[string]bool, error) {
+	var sections = make(map[string]bool)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == sectionAll {
+			for _, section := range textSections {
+				sections[section.name] = true
+			}
+			continue
+		}
+		if !isTextSection(name) {
+			return nil, fmt.Errorf("unknown section %q, expected %s or one of: %s", name, sectionAll, strings.Join(textSectionNames(), ", "))
+		}
+		sections[name] = true
+	}
+	return sections, nil
+}
+
+func isTextSection(name string) bool {
+	for _, section := range textSections {
+		if section.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func textSectionNames() []string {
+	var names []string
+	for _, section := range textSections {
+		names = append(names, section.name)
+	}
+	return names
+}
+
+// writeText writes the report as aligned, human friendly tables. Each line metric is shown along with its percentage of
+// the total lines processed, followed by a compact table per package. The textSections are only written if they are in
+// sections, while the gofmt, violations and warnings lists are always written when they are not empty.
+func writeText(w io.Writer, report Report, sections map[string]bool, color bool) error {
+	var buf bytes.Buffer
+
+	writeSummary(&buf, report.Total, color)
+	pkgs := report.packages()
+	if len(pkgs) > 0 {
+		writePackages(&buf, pkgs, color)
+		for _, section := range textSections {
+			if sections[section.name] {
+				section.write(&buf, report, pkgs, color)
+			}
+		}
+	}
+	writeUnformatted(&buf, report, color)
+	writeViolations(&buf, report, color)
+	writeList(&buf, "Warnings", report.warnings(), color)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeSummary(buf *bytes.Buffer, total Results, color bool) {
+	summary := textTable{
+		rightAlign: []bool{false, true, true},
+	}
+	summary.addRow("Files", fmt.Sprintf("%d", total.NumOfFiles), "")
+	summary.addRow("Total Lines", fmt.Sprintf("%d", total.TotalLinesProcessed), "")
+	for _, m := range []summaryMetric{
+		{"Lines of Code", func(r Results) int { return r.LinesOfCode }},
+		{"Lines of Err-Check", func(r Results) int { return r.LinesOfErrCheck }},
+		{"Lines of Comments", func(r Results) int { return r.LinesOfComments }},
+		{"Lines of Whitespace", func(r Results) int { return r.LinesWhitespace }},
+	} {
+		n := m.value(total)
+		summary.addRow(m.name, fmt.Sprintf("%d", n), formatPercent(percentOf(n, total.TotalLinesProcessed)))
+	}
+	// Only lines of code can be duplicated, so they are what the share is of, as in the clones section
+	summary.addRow("Lines Duplicated", fmt.Sprintf("%d", total.LinesDuplicated), formatPercent(duplicationRatio(total)*100))
+	summary.addRow("Inline Comments", fmt.Sprintf("%d", total.NumInlineComments), "")
+	summary.addRow("Logical Statements", fmt.Sprintf("%d", total.LogicalStatements), "")
+	summary.addRow("Max Curly Braces Depth", fmt.Sprintf("%d", total.MaxCurlyBracesDepth), "")
+	summary.render(buf, color)
+
+	if total.MaxCurlyBracesDepthLocation.File != "" {
+		fmt.Fprintf(buf, "  at %s\n", formatLocation(total.MaxCurlyBracesDepthLocation))
+	}
+}
+
+func writePackages(buf *bytes.Buffer, pkgs []PackageResults, color bool) {
+	header := []string{"Files", "Lines", "Code", "Err-Check", "Comments", "Whitespace", "Statements", "Lines/Stmt"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		return []string{
+			fmt.Sprintf("%d", p.NumOfFiles),
+			fmt.Sprintf("%d", p.TotalLinesProcessed),
+			formatPercent(percentOf(p.LinesOfCode, p.TotalLinesProcessed)),
+			formatPercent(percentOf(p.LinesOfErrCheck, p.TotalLinesProcessed)),
+			formatPercent(percentOf(p.LinesOfComments, p.TotalLinesProcessed)),
+			formatPercent(percentOf(p.LinesWhitespace, p.TotalLinesProcessed)),
+			fmt.Sprintf("%d", p.LogicalStatements),
+			formatRatio(p.LinesOfCode+p.LinesOfErrCheck, p.LogicalStatements),
+		}
+	})
+}
+
+func writeDeclarations(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	header := []string{"Structs", "Interfaces", "Aliases", "Types", "Funcs", "Methods", "Consts", "Vars", "Exported", "Unexported"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		d := p.Declarations
+		return intCells(d.Structs, d.Interfaces, d.TypeAliases, d.OtherTypes, d.Funcs, d.Methods, d.Consts, d.Vars, d.Exported, d.Unexported)
+	})
+}
+
+func writeDocCoverage(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	var coverage DocCoverage
+	writePackageTable(buf, pkgs, []string{"Exported", "Documented", "Doc Coverage"}, color, func(p PackageResults) []string {
+		c := p.DocCoverage
+		coverage = addDocCoverage(coverage, c)
+		return append(intCells(c.Exported, c.Documented), formatPercent(c.Percent()))
+	})
+
+	var lines []string
+	for _, sym := range coverage.Undocumented {
+		lines = append(lines, fmt.Sprintf("%s: %s %s", formatLocation(sym.Location), sym.Kind, sym.Name))
+	}
+	writeList(buf, "Undocumented", lines, color)
+}
+
+func writeComments(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	var comments CommentStats
+	header := []string{"Docs", "License", "Directives", "Commented-Out Code", "Other", "Inline", "Markers"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		c := p.Comments
+		comments = addCommentStats(comments, c)
+		return intCells(c.LinesOfDocs, c.LinesOfLicense, c.LinesOfDirectives, c.LinesOfCommentedOutCode, c.LinesOfOther, c.LinesOfInline, len(c.Markers))
+	})
+
+	var lines []string
+	for _, m := range comments.Markers {
+		lines = append(lines, fmt.Sprintf("%s: %s %s", formatLocation(m.Location), m.Kind, m.Text))
+	}
+	writeList(buf, "Markers", lines, color)
+}
+
+func writeErrorHandling(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	header := []string{"Bare Returns", "Wrapped", "Unwrapped", "Panics", "Fatals", "Ignored Calls", "Dropped Calls", "Wrapped %"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		e := p.ErrorHandling
+		return append(intCells(e.BareReturns, e.Wrapped, e.Unwrapped, e.Panics, e.Fatals, e.IgnoredCalls, e.DroppedCalls), formatPercent(e.Percent()))
+	})
+
+	// Err-check ratio per file and function
+	files := report.sortedFiles(func(a, b FileResults) bool {
+		return errCheckRatio(a.Results) > errCheckRatio(b.Results)
+	})
+	if len(files) > numMostBoilerplate {
+		files = files[:numMostBoilerplate]
+	}
+	fileTable := newTable(1, "File", "Code", "Err-Check", "Err-Check %")
+	for _, f := range files {
+		fileTable.addRow(append([]string{f.Path}, append(intCells(f.LinesOfCode, f.LinesOfErrCheck), formatPercent(errCheckRatio(f.Results)*100))...)...)
+	}
+	writeTable(buf, fileTable, color)
+
+	funcs := mostBoilerplate(report.funcs())
+	if len(funcs) > numMostBoilerplate {
+		funcs = funcs[:numMostBoilerplate]
+	}
+	funcTable := newTable(2, "Function", "Location", "Code", "Err-Check", "Err-Check %")
+	for _, f := range funcs {
+		funcTable.addRow(f.Name, formatLocation(f.Location), fmt.Sprintf("%d", f.LinesOfCode), fmt.Sprintf("%d", f.LinesOfErrCheck), formatPercent(f.errCheckRatio()*100))
+	}
+	writeTable(buf, funcTable, color)
+}
+
+func writeMaintainability(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	writePackageTable(buf, pkgs, []string{"Funcs", "Complexity", "Volume", "Effort", "Maintainability"}, color, func(p PackageResults) []string {
+		m := p.Maintainability
+		return []string{
+			fmt.Sprintf("%d", m.Funcs),
+			fmt.Sprintf("%d", m.Complexity),
+			fmt.Sprintf("%.0f", m.Volume),
+			fmt.Sprintf("%.0f", m.Effort),
+			fmt.Sprintf("%.1f", m.Index()),
+		}
+	})
+
+	files := leastMaintainableFiles(report)
+	if len(files) > numLeastMaintained {
+		files = files[:numLeastMaintained]
+	}
+	fileTable := newTable(1, "File", "Funcs", "Code", "Complexity", "Volume", "Effort", "Maintainability")
+	for _, f := range files {
+		m := f.Maintainability
+		fileTable.addRow(append(
+			append([]string{f.Path}, intCells(m.Funcs, m.LinesOfCode, m.Complexity)...),
+			fmt.Sprintf("%.0f", m.Volume),
+			fmt.Sprintf("%.0f", m.Effort),
+			fmt.Sprintf("%.1f", m.Index()),
+		)...)
+	}
+	writeTable(buf, fileTable, color)
+
+	funcs := leastMaintainable(report.funcs())
+	if len(funcs) > numLeastMaintained {
+		funcs = funcs[:numLeastMaintained]
+	}
+	funcTable := newTable(2, "Function", "Location", "Code", "Complexity", "Volume", "Difficulty", "Effort", "Maintainability")
+	for _, f := range funcs {
+		funcTable.addRow(
+			f.Name,
+			formatLocation(f.Location),
+			fmt.Sprintf("%d", f.LinesOfCode),
+			fmt.Sprintf("%d", f.Complexity),
+			fmt.Sprintf("%.0f", f.Halstead.Volume()),
+			fmt.Sprintf("%.1f", f.Halstead.Difficulty()),
+			fmt.Sprintf("%.0f", f.Halstead.Effort()),
+			fmt.Sprintf("%.1f", f.MaintainabilityIndex),
+		)
+	}
+	writeTable(buf, funcTable, color)
+}
+
+func writeSignatures(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	header := []string{"Funcs", "Params", "Params/Func", "Results", "Named Results", "Variadic", "Error Last", "Error Elsewhere"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		s := p.Signatures
+		return append(
+			[]string{fmt.Sprintf("%d", s.Funcs), fmt.Sprintf("%d", s.Params), fmt.Sprintf("%.1f", s.avgParams())},
+			intCells(s.Results, s.NamedResults, s.Variadic, s.ErrorLast, s.ErrorElsewhere)...,
+		)
+	})
+
+	funcs := longestParamLists(report.funcs())
+	if len(funcs) > numLongestParams {
+		funcs = funcs[:numLongestParams]
+	}
+	funcTable := newTable(2, "Longest Param Lists", "Location", "Params", "Results", "Variadic")
+	funcTable.rightAlign[4] = false
+	for _, f := range funcs {
+		var variadic string
+		if f.Signature.Variadic {
+			variadic = "yes"
+		}
+		funcTable.addRow(f.Name, formatLocation(f.Location), fmt.Sprintf("%d", f.Signature.Params), fmt.Sprintf("%d", f.Signature.Results), variadic)
+	}
+	writeTable(buf, funcTable, color)
+
+	var lines []string
+	for _, f := range report.funcs() {
+		if f.Signature.ErrorResult == errorResultElsewhere {
+			lines = append(lines, fmt.Sprintf("%s: %s", formatLocation(f.Location), f.Name))
+		}
+	}
+	writeList(buf, "Error Not Returned Last", lines, color)
+}
+
+func writeDeferPanic(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	var deferPanic DeferPanic
+	writePackageTable(buf, pkgs, []string{"Defers", "Defers in Loops", "Panics", "Recovers"}, color, func(p PackageResults) []string {
+		d := p.DeferPanic
+		deferPanic = addDeferPanic(deferPanic, d)
+		return intCells(d.Defers, len(d.DefersInLoops), len(d.Panics), len(d.Recovers))
+	})
+
+	for _, list := range []struct {
+		title   string
+		symbols []Symbol
+	}{
+		{"Panics Outside main/init", deferPanic.Panics},
+		{"Recovers", deferPanic.Recovers},
+	} {
+		var lines []string
+		for _, sym := range list.symbols {
+			lines = append(lines, fmt.Sprintf("%s: %s in %s", formatLocation(sym.Location), sym.Kind, sym.Name))
+		}
+		writeList(buf, list.title, lines, color)
+	}
+}
+
+func writeShapes(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	var shapes Shapes
+	header := []string{"Structs", "Fields", "Embedded", "Tagged", "Interfaces", "Methods", "Embedded"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		s := p.Shapes
+		shapes = addShapes(shapes, s)
+		return intCells(s.Structs, s.Fields, s.EmbeddedFields, s.TaggedFields, s.Interfaces, s.Methods, s.EmbeddedInterfaces)
+	})
+
+	tagTable := newTable(1, "Struct Tag", "Fields")
+	for _, c := range sortedCounts(shapes.Tags) {
+		tagTable.addRow(c.name, fmt.Sprintf("%d", c.count))
+	}
+	writeTable(buf, tagTable, color)
+
+	for _, largest := range []struct {
+		kind   string
+		header []string
+	}{
+		{shapeStruct, []string{"Largest Structs", "Location", "Fields", "Embedded"}},
+		{shapeInterface, []string{"Largest Interfaces", "Location", "Methods", "Embedded"}},
+	} {
+		types := largestTypes(shapes.Types, largest.kind)
+		if len(types) > numLargestTypes {
+			types = types[:numLargestTypes]
+		}
+		typeTable := newTable(2, largest.header...)
+		for _, t := range types {
+			typeTable.addRow(append([]string{t.Name, formatLocation(t.Location)}, intCells(t.Members, t.Embedded)...)...)
+		}
+		writeTable(buf, typeTable, color)
+	}
+}
+
+func writeGenerics(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	header := []string{"Generic Funcs", "Generic Types", "Type Params", "Constraints", "Instantiations"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		g := p.Generics
+		return intCells(g.Funcs, g.Types, g.TypeParams, g.Constraints, g.Instantiations)
+	})
+}
+
+func writeConcurrency(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	header := []string{"Goroutines", "Chan Makes", "Sends", "Receives", "Selects", "Mutexes", "RWMutexes", "WaitGroups", "Onces", "Context Params"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		return concurrencyCells(p.Concurrency)
+	})
+
+	funcs := mostConcurrent(report.funcs())
+	if len(funcs) > numMostConcurrent {
+		funcs = funcs[:numMostConcurrent]
+	}
+	funcTable := newTable(2, append([]string{"Function", "Location"}, header...)...)
+	for _, f := range funcs {
+		funcTable.addRow(append([]string{f.Name, formatLocation(f.Location)}, concurrencyCells(f.Concurrency)...)...)
+	}
+	writeTable(buf, funcTable, color)
+}
+
+// writeTests only writes something if the test files are not ignored
+func writeTests(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	var tests TestQuality
+	for _, p := range pkgs {
+		tests = addTestQuality(tests, p.Tests)
+	}
+	if tests.Tests == 0 {
+		return
+	}
+
+	header := []string{"Tests", "Table-Driven", "Subtests", "Parallel", "Assertions", "t.Error/Fatal", "Skips", "Mapped"}
+	writePackageTable(buf, pkgs, header, color, func(p PackageResults) []string {
+		q := p.Tests
+		return intCells(q.Tests, q.TableDriven, q.Subtests, q.Parallel, q.Assertions, q.Failures, q.Skips, q.Mapped)
+	})
+
+	var unmapped []TestFunc
+	for _, t := range tests.Funcs {
+		if t.Tested == "" {
+			unmapped = append(unmapped, t)
+		}
+	}
+	sort.Slice(unmapped, func(i, j int) bool {
+		return locationLess(unmapped[i].Location, unmapped[j].Location)
+	})
+	var lines []string
+	for _, t := range unmapped {
+		lines = append(lines, fmt.Sprintf("%s: %s", formatLocation(t.Location), t.Name))
+	}
+	writeList(buf, "Tests Not Named After a Function", lines, color)
+
+	untested := untestedFuncs(report)
+	untestedTable := newTable(4, fmt.Sprintf("Untested Exported Funcs (%d)", len(untested)), "Location", "Named Test", "Called")
+	for _, u := range untested {
+		var called string
+		if u.Called {
+			called = "yes"
+		}
+		untestedTable.addRow(u.Name, formatLocation(u.Location), u.Test, called)
+	}
+	writeTable(buf, untestedTable, color)
+}
+
+func writeImports(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	// The imports are listed by import path rather than by directory
+	importTable := newTable(1, "Package", "Std", "Internal", "Third-Party", "Fan-Out", "Fan-In", "Lines of Code")
+	for i, s := range importStats(pkgs) {
+		importTable.addRow(append([]string{s.Package}, intCells(s.Std, s.Internal, s.ThirdParty, s.FanOut, s.FanIn, pkgs[i].LinesOfCode)...)...)
+	}
+	writeTable(buf, importTable, color)
+
+	imported := mostImported(pkgs)
+	if len(imported) > numMostImported {
+		imported = imported[:numMostImported]
+	}
+	importedTable := newTable(1, "Most Imported", "Packages")
+	for _, c := range imported {
+		importedTable.addRow(c.name, fmt.Sprintf("%d", c.count))
+	}
+	writeTable(buf, importedTable, color)
+}
+
+func writeDirectives(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	directives := report.directives()
+	if len(directives) == 0 {
+		return
+	}
+	byKind, byLinter := countDirectives(directives)
+	for _, counts := range []struct {
+		header string
+		counts map[string]int
+	}{
+		{"Directive", byKind},
+		{"Suppressed Linter", byLinter},
+	} {
+		countTable := newTable(1, counts.header, "Count")
+		for _, c := range sortedCounts(counts.counts) {
+			countTable.addRow(c.name, fmt.Sprintf("%d", c.count))
+		}
+		writeTable(buf, countTable, color)
+	}
+
+	var lines []string
+	for _, d := range directives {
+		line := strings.TrimSpace(fmt.Sprintf("%s: %s %s", formatLocation(d.Location), d.Kind, d.Args))
+		if d.Risky {
+			line += " " + colorize("[risk]", colorBold, color)
+		}
+		lines = append(lines, line)
+	}
+	writeList(buf, "Directives", lines, color)
+}
+
+func writeClones(buf *bytes.Buffer, report Report, pkgs []PackageResults, color bool) {
+	if len(report.Clones) == 0 {
+		return
+	}
+	buf.WriteString("\n")
+	title := fmt.Sprintf("Clones (%d, %s of the code is duplicated)", len(report.Clones), formatPercent(duplicationRatio(report.Total)*100))
+	fmt.Fprintln(buf, colorize(title, colorBold+colorCyan, color))
+	groups := report.Clones
+	if len(groups) > numLargestClones {
+		groups = groups[:numLargestClones]
+	}
+	for _, g := range groups {
+		fmt.Fprintf(buf, "%d lines, %s:\n", g.Lines, g.Kind)
+		for _, c := range g.Clones {
+			fmt.Fprintf(buf, "  %s:%d-%d\n", c.File, c.StartLine, c.EndLine)
+		}
+	}
+}
+
+// writeUnformatted lists the files that are not gofmt-ed, which are only found with --gofmt
+func writeUnformatted(buf *bytes.Buffer, report Report, color bool) {
+	var lines []string
+	for _, f := range report.filesByPath() {
+		if f.NumOfUnformattedFiles > 0 {
+			lines = append(lines, f.Path)
+		}
+	}
+	writeList(buf, "Not Formatted by gofmt", lines, color)
+}
+
+func writeViolations(buf *bytes.Buffer, report Report, color bool) {
+	var lines []string
+	for _, v := range report.violations() {
+		lines = append(lines, fmt.Sprintf("%s:%d: %s [%s]", v.File, v.Region.StartLine, v.Message, v.Rule))
+	}
+	writeList(buf, "Violations", lines, color)
+}
+
+// writePackageTable writes a table with a row per package: the path of the package, followed by the cells returned for
+// it. The header does not include the package column.
+func writePackageTable(buf *bytes.Buffer, pkgs []PackageResults, header []string, color bool, cells func(p PackageResults) []string) {
+	t := newTable(1, append([]string{"Package"}, header...)...)
+	for _, p := range pkgs {
+		t.addRow(append([]string{p.Path}, cells(p)...)...)
+	}
+	writeTable(buf, t, color)
+}
+
+// writeTable writes the table after an empty line, unless it has no rows
+func writeTable(buf *bytes.Buffer, t textTable, color bool) {
+	if len(t.rows) == 0 {
+		return
+	}
+	buf.WriteString("\n")
+	t.render(buf, color)
+}
+
+// writeList writes a title with the number of lines after an empty line, followed by the lines, unless there are none
+func writeList(buf *bytes.Buffer, title string, lines []string, color bool) {
+	if len(lines) == 0 {
+		return
+	}
+	buf.WriteString("\n")
+	fmt.Fprintln(buf, colorize(fmt.Sprintf("%s (%d)", title, len(lines)), colorBold+colorCyan, color))
+	for _, line := range lines {
+		fmt.Fprintln(buf, line)
+	}
+}
+
+func formatPercent(p float64) string {
+	return fmt.Sprintf("%.1f%%", p)
+}
+
+// formatRatio formats n/d, or a dash when d is zero
+func formatRatio(n, d int) string {
+	if d == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", float64(n)/float64(d))
+}
+
+func formatLocation(l Location) string {
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// intCells formats each number as a cell
+func intCells(numbers ...int) []string {
+	var cells []string
+	for _, n := range numbers {
+		cells = append(cells, fmt.Sprintf("%d", n))
+	}
+	return cells
+}
+
+func concurrencyCells(c Concurrency) []string {
+	return intCells(c.Goroutines, c.ChanMakes, c.ChanSends, c.ChanReceives, c.Selects, c.Mutexes, c.RWMutexes, c.WaitGroups, c.Onces, c.ContextParams)
+}
+
+// textTable lays out rows of cells in aligned columns
+type textTable struct {
+	header     []string
+	rows       [][]string
+	rightAlign []bool // whether the column at the index should be right aligned
+}
+
+// newTable returns a table with the header, whose first numLabels columns are left aligned and the rest right aligned
+func newTable(numLabels int, header ...string) textTable {
+	t := textTable{header: header}
+	for i := range header {
+		t.rightAlign = append(t.rightAlign, i >= numLabels)
+	}
+	return t
+}
+
+func (t *textTable) addRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// render writes the table to w, separating columns by two spaces. Color codes are only added after the cells have been
+// padded, so that they don't affect the alignment.
+func (t textTable) render(w io.Writer, color bool) {
+	var widths []int
+	for _, row := range append([][]string{t.header}, t.rows...) {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = maxInt(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	if len(t.header) > 0 {
+		line := t.formatRow(t.header, widths)
+		fmt.Fprintln(w, colorize(line, colorBold+colorCyan, color))
+	}
+	for _, row := range t.rows {
+		var cells []string
+		for i, cell := range t.padRow(row, widths) {
+			// The first column is the label, the rest are values
+			if i == 0 {
+				cell = colorize(cell, colorBold, color)
+			} else if strings.HasSuffix(row[i], "%") {
+				cell = colorize(cell, colorDim, color)
+			}
+			cells = append(cells, cell)
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
+func (t textTable) formatRow(row []string, widths []int) string {
+	return strings.TrimRight(strings.Join(t.padRow(row, widths), "  "), " ")
+}
+
+func (t textTable) padRow(row []string, widths []int) []string {
+	var cells []string
+	for i, cell := range row {
+		padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if i < len(t.rightAlign) && t.rightAlign[i] {
+			cells = append(cells, padding+cell)
+		} else {
+			cells = append(cells, cell+padding)
+		}
+	}
+	return cells
+}
+
+func colorize(s, code string, enabled bool) string {
+	if !enabled || s == "" {
+		return s
+	}
+	return code + s + colorReset
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteText(t *testing.T) {
+	var sampleReport = Report{
+		Total: Results{
+			NumOfFiles:            2,
+			LinesOfCode:           60,
+			LinesOfErrCheck:       10,
+			LinesOfComments:       10,
+			LinesWhitespace:       20,
+			TotalLinesProcessed:   100,
+			NumInlineComments:     1,
+			LogicalStatements:     45,
+			NumOfUnformattedFiles: 1,
+			LinesDuplicated:       14,
+			MaxCurlyBracesDepth:   3,
+			MaxCurlyBracesDepthLocation: Location{
+				File: "pkg/a.go",
+				Line: 12,
+			},
+		},
+		Clones: []CloneGroup{
+			{
+				Kind:  cloneNormalized,
+				Lines: 7,
+				Clones: []Clone{
+					{File: "pkg/a.go", StartLine: 20, EndLine: 27},
+					{File: "pkg/a.go", StartLine: 40, EndLine: 48},
+				},
+			},
+		},
+		Files: []FileResults{
+			{
+				Path:    "pkg/a.go",
+				Package: "example.com/app/pkg",
+				Results: Results{
+					NumOfFiles:          1,
+					LinesOfCode:         50,
+					LinesOfErrCheck:     10,
+					LinesOfComments:     10,
+					LinesWhitespace:     10,
+					TotalLinesProcessed: 80,
+					LogicalStatements:   40,
+				},
+				Declarations: Declarations{
+					Structs:    2,
+					Interfaces: 1,
+					Funcs:      3,
+					Methods:    4,
+					Exported:   6,
+					Unexported: 4,
+				},
+				ErrorHandling: ErrorHandling{
+					BareReturns: 2,
+					Wrapped:     1,
+					Unwrapped:   1,
+				},
+				Funcs: []FuncStats{
+					{
+						Name: "Open", Location: Location{File: "pkg/a.go", Line: 20}, LinesOfCode: 8, LinesOfErrCheck: 6,
+						Signature:  Signature{Params: 2, Results: 2, ErrorResult: errorResultLast},
+						Complexity: 4, Halstead: Halstead{Operators: 40, Operands: 30, DistinctOperators: 10, DistinctOperands: 6}, MaintainabilityIndex: 60.5,
+					},
+					{
+						Name: "Reader.Read", Location: Location{File: "pkg/a.go", Line: 40}, LinesOfCode: 12, LinesOfErrCheck: 4,
+						Concurrency: Concurrency{Goroutines: 2, ChanMakes: 1, ContextParams: 1},
+						Signature:   Signature{Params: 2, Results: 2, NamedResults: true, ErrorResult: errorResultLast},
+						Complexity:  2, Halstead: Halstead{Operators: 20, Operands: 12, DistinctOperators: 8, DistinctOperands: 8}, MaintainabilityIndex: 72,
+					},
+					{
+						Name: "helper", Location: Location{File: "pkg/a.go", Line: 60}, LinesOfCode: 5,
+						Signature:  Signature{Params: 4, Results: 2, Variadic: true, ErrorResult: errorResultElsewhere},
+						Complexity: 1, MaintainabilityIndex: 90,
+					},
+				},
+				Tests: TestQuality{
+					Tests:       3,
+					TableDriven: 2,
+					Subtests:    2,
+					Assertions:  7,
+					Failures:    1,
+					Skips:       1,
+					Mapped:      2,
+					Funcs: []TestFunc{
+						{Name: "TestOpen", Location: Location{File: "pkg/a_test.go", Line: 10}, TableDriven: true, Tested: "Open"},
+						{Name: "TestReader_Read", Location: Location{File: "pkg/a_test.go", Line: 30}, TableDriven: true, Tested: "Reader.Read"},
+						{Name: "TestIntegration", Location: Location{File: "pkg/a_test.go", Line: 50}},
+					},
+				},
+				TestCalls: []string{"Errorf", "Open"},
+				Signatures: Signatures{
+					Funcs:          3,
+					Params:         8,
+					Results:        6,
+					NamedResults:   1,
+					Variadic:       1,
+					ErrorLast:      2,
+					ErrorElsewhere: 1,
+				},
+				Maintainability: Maintainability{
+					Funcs:         3,
+					Complexity:    7,
+					Volume:        408,
+					Effort:        7768,
+					LinesOfCode:   25,
+					WeightedIndex: 1798,
+				},
+				DeferPanic: DeferPanic{
+					Defers:        3,
+					DefersInLoops: []Symbol{{Name: "Reader.Read", Kind: "defer", Location: Location{File: "pkg/a.go", Line: 45}}},
+					Panics:        []Symbol{{Name: "Open", Kind: "panic", Location: Location{File: "pkg/a.go", Line: 25}}},
+				},
+				Shapes: Shapes{
+					Structs:            2,
+					Fields:             7,
+					EmbeddedFields:     1,
+					TaggedFields:       4,
+					Tags:               map[string]int{"json": 4, "db": 2},
+					Interfaces:         1,
+					Methods:            6,
+					EmbeddedInterfaces: 1,
+					Types: []TypeShape{
+						{Name: "Reader", Kind: shapeInterface, Location: Location{File: "pkg/a.go", Line: 8}, Members: 6, Embedded: 1},
+						{Name: "options", Kind: shapeStruct, Location: Location{File: "pkg/a.go", Line: 30}, Members: 2},
+						{Name: "Config", Kind: shapeStruct, Location: Location{File: "pkg/a.go", Line: 12}, Members: 5, Embedded: 1},
+					},
+				},
+				Generics: Generics{
+					Funcs:       1,
+					Types:       1,
+					TypeParams:  3,
+					Constraints: 1,
+				},
+				GenericNames: []string{"Map", "Set"},
+				GenericRefs: []Symbol{
+					{Name: "Map", Kind: "instantiation", Location: Location{File: "pkg/a.go", Line: 70}},
+					{Name: "Open", Kind: "instantiation", Location: Location{File: "pkg/a.go", Line: 71}},
+				},
+				Concurrency: Concurrency{
+					Goroutines:    2,
+					ChanMakes:     1,
+					Mutexes:       1,
+					ContextParams: 3,
+				},
+				DocCoverage: DocCoverage{
+					Exported:   6,
+					Documented: 5,
+					Undocumented: []Symbol{
+						{Name: "Reader", Kind: symbolType, Location: Location{File: "pkg/a.go", Line: 8}},
+					},
+				},
+			},
+			{
+				Path:    "main.go",
+				Package: "example.com/app",
+				GenericRefs: []Symbol{
+					{Name: "Set", Kind: "instantiation", Location: Location{File: "main.go", Line: 5}},
+				},
+				Imports: []Import{
+					{Path: "example.com/app/pkg", Kind: importInternal},
+					{Path: "fmt", Kind: importStd},
+				},
+				Results: Results{
+					NumOfFiles:            1,
+					LinesOfCode:           10,
+					LinesWhitespace:       10,
+					TotalLinesProcessed:   20,
+					LogicalStatements:     5,
+					NumOfUnformattedFiles: 1,
+				},
+			},
+		},
+	}
+
+	allSections, err := parseSections(sectionAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		report   Report
+		sections map[string]bool
+		want     string
+	}{
+		{
+			name: "empty report",
+			want: "Files                   0\n" +
+				"Total Lines             0\n" +
+				"Lines of Code           0  0.0%\n" +
+				"Lines of Err-Check      0  0.0%\n" +
+				"Lines of Comments       0  0.0%\n" +
+				"Lines of Whitespace     0  0.0%\n" +
+				"Lines Duplicated        0  0.0%\n" +
+				"Inline Comments         0\n" +
+				"Logical Statements      0\n" +
+				"Max Curly Braces Depth  0\n",
+		},
+		{
+			name:   "no sections",
+			report: sampleReport,
+			want: "Files                     2\n" +
+				"Total Lines             100\n" +
+				"Lines of Code            60  60.0%\n" +
+				"Lines of Err-Check       10  10.0%\n" +
+				"Lines of Comments        10  10.0%\n" +
+				"Lines of Whitespace      20  20.0%\n" +
+				"Lines Duplicated         14  20.0%\n" +
+				"Inline Comments           1\n" +
+				"Logical Statements       45\n" +
+				"Max Curly Braces Depth    3\n" +
+				"  at pkg/a.go:12\n" +
+				"\n" +
+				"Package  Files  Lines   Code  Err-Check  Comments  Whitespace  Statements  Lines/Stmt\n" +
+				".            1     20  50.0%       0.0%      0.0%       50.0%           5        2.00\n" +
+				"pkg          1     80  62.5%      12.5%     12.5%       12.5%          40        1.50\n" +
+				"\n" +
+				"Not Formatted by gofmt (1)\n" +
+				"main.go\n",
+		},
+		{
+			name:     "all sections",
+			report:   sampleReport,
+			sections: allSections,
+			want: "Files                     2\n" +
+				"Total Lines             100\n" +
+				"Lines of Code            60  60.0%\n" +
+				"Lines of Err-Check       10  10.0%\n" +
+				"Lines of Comments        10  10.0%\n" +
+				"Lines of Whitespace      20  20.0%\n" +
+				"Lines Duplicated         14  20.0%\n" +
+				"Inline Comments           1\n" +
+				"Logical Statements       45\n" +
+				"Max Curly Braces Depth    3\n" +
+				"  at pkg/a.go:12\n" +
+				"\n" +
+				"Package  Files  Lines   Code  Err-Check  Comments  Whitespace  Statements  Lines/Stmt\n" +
+				".            1     20  50.0%       0.0%      0.0%       50.0%           5        2.00\n" +
+				"pkg          1     80  62.5%      12.5%     12.5%       12.5%          40        1.50\n" +
+				"\n" +
+				"Package  Structs  Interfaces  Aliases  Types  Funcs  Methods  Consts  Vars  Exported  Unexported\n" +
+				".              0           0        0      0      0        0       0     0         0           0\n" +
+				"pkg            2           1        0      0      3        4       0     0         6           4\n" +
+				"\n" +
+				"Package  Exported  Documented  Doc Coverage\n" +
+				".               0           0        100.0%\n" +
+				"pkg             6           5         83.3%\n" +
+				"\n" +
+				"Undocumented (1)\n" +
+				"pkg/a.go:8: type Reader\n" +
+				"\n" +
+				"Package  Docs  License  Directives  Commented-Out Code  Other  Inline  Markers\n" +
+				".           0        0           0                   0      0       0        0\n" +
+				"pkg         0        0           0                   0      0       0        0\n" +
+				"\n" +
+				"Package  Bare Returns  Wrapped  Unwrapped  Panics  Fatals  Ignored Calls  Dropped Calls  Wrapped %\n" +
+				".                   0        0          0       0       0              0              0     100.0%\n" +
+				"pkg                 2        1          1       0       0              0              0      25.0%\n" +
+				"\n" +
+				"File      Code  Err-Check  Err-Check %\n" +
+				"pkg/a.go    50         10        16.7%\n" +
+				"main.go     10          0         0.0%\n" +
+				"\n" +
+				"Function     Location     Code  Err-Check  Err-Check %\n" +
+				"Open         pkg/a.go:20     8          6        42.9%\n" +
+				"Reader.Read  pkg/a.go:40    12          4        25.0%\n" +
+				"\n" +
+				"Package  Funcs  Complexity  Volume  Effort  Maintainability\n" +
+				".            0           0       0       0            100.0\n" +
+				"pkg          3           7     408    7768             71.9\n" +
+				"\n" +
+				"File      Funcs  Code  Complexity  Volume  Effort  Maintainability\n" +
+				"pkg/a.go      3    25           7     408    7768             71.9\n" +
+				"\n" +
+				"Function     Location     Code  Complexity  Volume  Difficulty  Effort  Maintainability\n" +
+				"Open         pkg/a.go:20     8           4     280        25.0    7000             60.5\n" +
+				"Reader.Read  pkg/a.go:40    12           2     128         6.0     768             72.0\n" +
+				"helper       pkg/a.go:60     5           1       0         0.0       0             90.0\n" +
+				"\n" +
+				"Package  Funcs  Params  Params/Func  Results  Named Results  Variadic  Error Last  Error Elsewhere\n" +
+				".            0       0          0.0        0              0         0           0                0\n" +
+				"pkg          3       8          2.7        6              1         1           2                1\n" +
+				"\n" +
+				"Longest Param Lists  Location     Params  Results  Variadic\n" +
+				"helper               pkg/a.go:60       4        2  yes\n" +
+				"Open                 pkg/a.go:20       2        2\n" +
+				"Reader.Read          pkg/a.go:40       2        2\n" +
+				"\n" +
+				"Error Not Returned Last (1)\n" +
+				"pkg/a.go:60: helper\n" +
+				"\n" +
+				"Package  Defers  Defers in Loops  Panics  Recovers\n" +
+				".             0                0       0         0\n" +
+				"pkg           3                1       1         0\n" +
+				"\n" +
+				"Panics Outside main/init (1)\n" +
+				"pkg/a.go:25: panic in Open\n" +
+				"\n" +
+				"Package  Structs  Fields  Embedded  Tagged  Interfaces  Methods  Embedded\n" +
+				".              0       0         0       0           0        0         0\n" +
+				"pkg            2       7         1       4           1        6         1\n" +
+				"\n" +
+				"Struct Tag  Fields\n" +
+				"json             4\n" +
+				"db               2\n" +
+				"\n" +
+				"Largest Structs  Location     Fields  Embedded\n" +
+				"Config           pkg/a.go:12       5         1\n" +
+				"options          pkg/a.go:30       2         0\n" +
+				"\n" +
+				"Largest Interfaces  Location    Methods  Embedded\n" +
+				"Reader              pkg/a.go:8        6         1\n" +
+				"\n" +
+				"Package  Generic Funcs  Generic Types  Type Params  Constraints  Instantiations\n" +
+				".                    0              0            0            0               1\n" +
+				"pkg                  1              1            3            1               1\n" +
+				"\n" +
+				"Package  Goroutines  Chan Makes  Sends  Receives  Selects  Mutexes  RWMutexes  WaitGroups  Onces  Context Params\n" +
+				".                 0           0      0         0        0        0          0           0      0               0\n" +
+				"pkg               2           1      0         0        0        1          0           0      0               3\n" +
+				"\n" +
+				"Function     Location     Goroutines  Chan Makes  Sends  Receives  Selects  Mutexes  RWMutexes  WaitGroups  Onces  Context Params\n" +
+				"Reader.Read  pkg/a.go:40           2           1      0         0        0        0          0           0      0               1\n" +
+				"\n" +
+				"Package  Tests  Table-Driven  Subtests  Parallel  Assertions  t.Error/Fatal  Skips  Mapped\n" +
+				".            0             0         0         0           0              0      0       0\n" +
+				"pkg          3             2         2         0           7              1      1       2\n" +
+				"\n" +
+				"Tests Not Named After a Function (1)\n" +
+				"pkg/a_test.go:50: TestIntegration\n" +
+				"\n" +
+				"Untested Exported Funcs (1)  Location     Named Test       Called\n" +
+				"Reader.Read                  pkg/a.go:40  TestReader_Read\n" +
+				"\n" +
+				"Package              Std  Internal  Third-Party  Fan-Out  Fan-In  Lines of Code\n" +
+				"example.com/app        1         1            0        2       0             10\n" +
+				"example.com/app/pkg    0         0            0        0       1             50\n" +
+				"\n" +
+				"Most Imported        Packages\n" +
+				"example.com/app/pkg         1\n" +
+				"fmt                         1\n" +
+				"\n" +
+				"Clones (1, 20.0% of the code is duplicated)\n" +
+				"7 lines, normalized:\n" +
+				"  pkg/a.go:20-27\n" +
+				"  pkg/a.go:40-48\n" +
+				"\n" +
+				"Not Formatted by gofmt (1)\n" +
+				"main.go\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeText(&buf, tt.report, tt.sections, false)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestColorize(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		enabled bool
+		want    string
+	}{
+		{
+			name:    "disabled",
+			s:       "text",
+			enabled: false,
+			want:    "text",
+		},
+		{
+			name:    "enabled",
+			s:       "text",
+			enabled: true,
+			want:    "\033[1mtext\033[0m",
+		},
+		{
+			name:    "empty string is not colored",
+			s:       "",
+			enabled: true,
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := colorize(tt.s, colorBold, tt.enabled)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	got, err := parseSections("docs, errors,,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"docs": true, "errors": true}, got)
+
+	got, err = parseSections(sectionAll)
+	assert.NoError(t, err)
+	assert.Equal(t, len(textSections), len(got))
+
+	_, err = parseSections("docs,nope")
+	assert.True(t, err != nil)
+}
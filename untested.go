@@ -0,0 +1,85 @@
+package main
+
+import (
+	"go/ast"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UntestedFunc is an exported func of a non-test file that no test is named after, or that no test file calls
+type UntestedFunc struct {
+	Name     string
+	Location Location
+	Test     string // test named after the func, empty if there is none
+	Called   bool   // whether a func or method of the same name is called from a test file
+}
+
+// findTestCalls returns the names of the funcs and methods called from the file, sorted
+func findTestCalls(file *ast.File) []string {
+	var names = make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if name := calledName(call.Fun); name != "" {
+				names[name] = true
+			}
+		}
+		return true
+	})
+
+	var calls []string
+	for name := range names {
+		calls = append(calls, name)
+	}
+	sort.Strings(calls)
+	return calls
+}
+
+// untestedFuncs finds the exported funcs and methods that have no test named after them, or that are not called from
+// any test file. The tests must have been mapped to their funcs. Calls are matched by name only, so a call to a method
+// of another type, or to a func of another package, with the same name counts as well.
+func untestedFuncs(report Report) []UntestedFunc {
+	// Tests by the func they are named after, joined to their dir, and the names called from the test files
+	var tests = make(map[string]TestFunc)
+	var called = make(map[string]bool)
+	for _, f := range report.Files {
+		for _, t := range f.Tests.Funcs {
+			if t.Tested == "" {
+				continue
+			}
+			key := filepath.Join(filepath.Dir(f.Path), t.Tested)
+			if prev, ok := tests[key]; !ok || locationLess(t.Location, prev.Location) {
+				tests[key] = t
+			}
+		}
+		for _, name := range f.TestCalls {
+			called[name] = true
+		}
+	}
+
+	var untested []UntestedFunc
+	for _, f := range report.Files {
+		if isTestFile(f.Path) || f.Generated {
+			continue
+		}
+		for _, fn := range f.Funcs {
+			parts := strings.Split(fn.Name, ".")
+			if !ast.IsExported(parts[0]) || !ast.IsExported(parts[len(parts)-1]) {
+				continue
+			}
+			u := UntestedFunc{
+				Name:     fn.Name,
+				Location: fn.Location,
+				Test:     tests[filepath.Join(filepath.Dir(f.Path), fn.Name)].Name,
+				Called:   called[parts[len(parts)-1]],
+			}
+			if u.Test == "" || !u.Called {
+				untested = append(untested, u)
+			}
+		}
+	}
+	sort.SliceStable(untested, func(i, j int) bool {
+		return locationLess(untested[i].Location, untested[j].Location)
+	})
+	return untested
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTestCalls(t *testing.T) {
+	src := `package sample_test
+
+func TestOpen(t *testing.T) {
+	r, err := sample.Open("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	check(t, func() { r.Read(nil) })
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "sample_test.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"Close", "Fatal", "Open", "Read", "check"}, findTestCalls(file))
+}
+
+func TestUntestedFuncs(t *testing.T) {
+	report := Report{
+		Files: []FileResults{
+			{
+				Path: "pkg/a.go",
+				Funcs: []FuncStats{
+					{Name: "Open", Location: Location{File: "pkg/a.go", Line: 5}},
+					{Name: "Reader.Read", Location: Location{File: "pkg/a.go", Line: 20}},
+					{Name: "Reader.Close", Location: Location{File: "pkg/a.go", Line: 30}},
+					{Name: "Reader.reset", Location: Location{File: "pkg/a.go", Line: 40}},
+					{Name: "reader.Read", Location: Location{File: "pkg/a.go", Line: 50}},
+					{Name: "Parse", Location: Location{File: "pkg/a.go", Line: 60}},
+					{Name: "helper", Location: Location{File: "pkg/a.go", Line: 70}},
+				},
+			},
+			{
+				Path:      "pkg/a_test.go",
+				Funcs:     []FuncStats{{Name: "TestOpen", Location: Location{File: "pkg/a_test.go", Line: 5}}},
+				Tests:     TestQuality{Funcs: []TestFunc{{Name: "TestOpen", Tested: "Open"}, {Name: "TestReader_Read", Tested: "Reader.Read"}}},
+				TestCalls: []string{"Close", "Open"},
+			},
+			{
+				Path:      "gen/b.go",
+				Generated: true,
+				Funcs:     []FuncStats{{Name: "Generated", Location: Location{File: "gen/b.go", Line: 1}}},
+			},
+		},
+	}
+
+	assert.Equal(t, []UntestedFunc{
+		{Name: "Reader.Read", Location: Location{File: "pkg/a.go", Line: 20}, Test: "TestReader_Read"},
+		{Name: "Reader.Close", Location: Location{File: "pkg/a.go", Line: 30}, Called: true},
+		{Name: "Parse", Location: Location{File: "pkg/a.go", Line: 60}},
+	}, untestedFuncs(report))
+}
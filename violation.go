@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// Rules that a Violation can be reported for
+const (
+	ruleFuncLength    = "func-length"
+	ruleNestingDepth  = "nesting-depth"
+	ruleFileLength    = "file-length"
+	ruleInterfaceSize = "interface-size"
+	ruleParamCount    = "param-count"
+)
+
+// thresholds are the limits above which a Violation is reported. A zero value disables the check.
+type thresholds struct {
+	maxFuncLines        int
+	maxNestingDepth     int
+	maxFileLines        int
+	maxInterfaceMethods int
+	maxParams           int
+}
+
+// Violation represents a piece of code that exceeds one of the configured thresholds
+type Violation struct {
+	Rule    string
+	Message string
+	File    string
+	Region  Region
+}
+
+// Region is the span of code a Violation refers to. Lines and columns start at 1.
+type Region struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+func regionOf(fset *token.FileSet, node ast.Node) Region {
+	start, end := fset.Position(node.Pos()), fset.Position(node.End())
+	return Region{
+		StartLine:   start.Line,
+		StartColumn: start.Column,
+		EndLine:     end.Line,
+		EndColumn:   end.Column,
+	}
+}
+
+// checkThresholds returns the Violations of the thresholds found in a single parsed file
+func checkThresholds(fset *token.FileSet, file *ast.File, fr FileResults, limits thresholds) []Violation {
+	var violations []Violation
+
+	// Functions that are too long
+	if limits.maxFuncLines > 0 {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			region := regionOf(fset, fn)
+			numLines := region.EndLine - region.StartLine + 1
+			if numLines > limits.maxFuncLines {
+				violations = append(violations, Violation{
+					Rule:    ruleFuncLength,
+					Message: fmt.Sprintf("function %s has %d lines (max %d)", funcName(fn), numLines, limits.maxFuncLines),
+					File:    fr.Path,
+					Region:  region,
+				})
+			}
+		}
+	}
+
+	// Nesting that is too deep
+	if limits.maxNestingDepth > 0 {
+		for _, loc := range fr.DeepNesting {
+			if loc.Depth <= limits.maxNestingDepth {
+				continue
+			}
+			msg := fmt.Sprintf("curly braces are nested %d levels deep (max %d)", loc.Depth, limits.maxNestingDepth)
+			if loc.Function != "" {
+				msg = fmt.Sprintf("%s in function %s", msg, loc.Function)
+			}
+			violations = append(violations, Violation{
+				Rule:    ruleNestingDepth,
+				Message: msg,
+				File:    fr.Path,
+				Region:  Region{StartLine: loc.Line, EndLine: loc.Line},
+			})
+		}
+	}
+
+	// File that is too long
+	if limits.maxFileLines > 0 && fr.TotalLinesProcessed > limits.maxFileLines {
+		violations = append(violations, Violation{
+			Rule:    ruleFileLength,
+			Message: fmt.Sprintf("file has %d lines (max %d)", fr.TotalLinesProcessed, limits.maxFileLines),
+			File:    fr.Path,
+			Region:  Region{StartLine: 1, EndLine: fr.TotalLinesProcessed},
+		})
+	}
+
+	// Interfaces with too many methods
+	if limits.maxInterfaceMethods > 0 {
+		for _, t := range fr.Shapes.Types {
+			if t.Kind != shapeInterface || t.Members <= limits.maxInterfaceMethods {
+				continue
+			}
+			violations = append(violations, Violation{
+				Rule:    ruleInterfaceSize,
+				Message: fmt.Sprintf("interface %s has %d methods (max %d)", t.Name, t.Members, limits.maxInterfaceMethods),
+				File:    fr.Path,
+				Region:  Region{StartLine: t.Location.Line, EndLine: t.Location.Line},
+			})
+		}
+	}
+
+	// Functions with too many params
+	if limits.maxParams > 0 {
+		for _, f := range fr.Funcs {
+			if f.Signature.Params <= limits.maxParams {
+				continue
+			}
+			violations = append(violations, Violation{
+				Rule:    ruleParamCount,
+				Message: fmt.Sprintf("function %s has %d params (max %d)", f.Name, f.Signature.Params, limits.maxParams),
+				File:    fr.Path,
+				Region:  Region{StartLine: f.Location.Line, EndLine: f.Location.Line},
+			})
+		}
+	}
+
+	return violations
+}
+
+// funcName returns the name of the function, prefixed by the receiver type for methods (e.g. "Report.packages")
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return receiverTypeName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+}
+
+// enclosingFuncName returns the name of the function declaration that contains the given line, or an empty string if
+// the line is outside of all functions
+func enclosingFuncName(fset *token.FileSet, file *ast.File, line int) string {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		region := regionOf(fset, fn)
+		if line >= region.StartLine && line <= region.EndLine {
+			return funcName(fn)
+		}
+	}
+	return ""
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// violations returns all the Violations found in the report, sorted by file and position
+func (r Report) violations() []Violation {
+	var all []Violation
+	all = append(all, r.Violations...)
+	for _, f := range r.Files {
+		all = append(all, f.Violations...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].File != all[j].File {
+			return all[i].File < all[j].File
+		}
+		if all[i].Region.StartLine != all[j].Region.StartLine {
+			return all[i].Region.StartLine < all[j].Region.StartLine
+		}
+		return all[i].Rule < all[j].Rule
+	})
+	return all
+}
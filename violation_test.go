@@ -0,0 +1,151 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckThresholds(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "config.go", sampleFileA, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sampleFileResults = FileResults{
+		Path: "config.go",
+		Results: Results{
+			TotalLinesProcessed: 96,
+			MaxCurlyBracesDepth: 2,
+			MaxCurlyBracesDepthLocation: Location{
+				File: "config.go",
+				Line: 16,
+			},
+			DeepNesting: []NestingLocation{
+				{Location: Location{File: "config.go", Line: 16}, Depth: 2},
+				{Location: Location{File: "config.go", Line: 74}, Depth: 2, Function: "ReadConfigTOML"},
+			},
+		},
+		Shapes: Shapes{
+			Types: []TypeShape{
+				{Name: "Config", Kind: shapeStruct, Location: Location{File: "config.go", Line: 20}, Members: 9},
+				{Name: "Store", Kind: shapeInterface, Location: Location{File: "config.go", Line: 40}, Members: 6},
+			},
+		},
+		Funcs: []FuncStats{
+			{Name: "ReadConfigTOML", Location: Location{File: "config.go", Line: 71}, Signature: Signature{Params: 2, Results: 2}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		limits thresholds
+		want   []Violation
+	}{
+		{
+			name: "no thresholds",
+		},
+		{
+			name: "thresholds not exceeded",
+			limits: thresholds{
+				maxFuncLines:        30,
+				maxNestingDepth:     2,
+				maxFileLines:        100,
+				maxInterfaceMethods: 6,
+				maxParams:           2,
+			},
+		},
+		{
+			name: "thresholds exceeded",
+			limits: thresholds{
+				maxFuncLines:        20,
+				maxNestingDepth:     1,
+				maxFileLines:        50,
+				maxInterfaceMethods: 5,
+				maxParams:           1,
+			},
+			want: []Violation{
+				{
+					Rule:    ruleFuncLength,
+					Message: "function ReadConfigTOML has 26 lines (max 20)",
+					File:    "config.go",
+					Region:  Region{StartLine: 71, StartColumn: 1, EndLine: 96, EndColumn: 2},
+				},
+				{
+					Rule:    ruleNestingDepth,
+					Message: "curly braces are nested 2 levels deep (max 1)",
+					File:    "config.go",
+					Region:  Region{StartLine: 16, EndLine: 16},
+				},
+				{
+					Rule:    ruleNestingDepth,
+					Message: "curly braces are nested 2 levels deep (max 1) in function ReadConfigTOML",
+					File:    "config.go",
+					Region:  Region{StartLine: 74, EndLine: 74},
+				},
+				{
+					Rule:    ruleFileLength,
+					Message: "file has 96 lines (max 50)",
+					File:    "config.go",
+					Region:  Region{StartLine: 1, EndLine: 96},
+				},
+				{
+					Rule:    ruleInterfaceSize,
+					Message: "interface Store has 6 methods (max 5)",
+					File:    "config.go",
+					Region:  Region{StartLine: 40, EndLine: 40},
+				},
+				{
+					Rule:    ruleParamCount,
+					Message: "function ReadConfigTOML has 2 params (max 1)",
+					File:    "config.go",
+					Region:  Region{StartLine: 71, EndLine: 71},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkThresholds(fset, file, sampleFileResults, tt.limits)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnclosingFuncName(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "config.go", sampleFileA, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		line int
+		want string
+	}{
+		{
+			name: "inside a type declaration",
+			line: 16,
+			want: "",
+		},
+		{
+			name: "first line of a function",
+			line: 71,
+			want: "ReadConfigTOML",
+		},
+		{
+			name: "inside a function",
+			line: 84,
+			want: "ReadConfigTOML",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, enclosingFuncName(fset, file, tt.line))
+		})
+	}
+}